@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dadosjusbr/storage/models"
+)
+
+// Default parameters of the anomaly detection.
+const (
+	defaultAnomalyWindow          = 12
+	defaultAnomalyMinObservations = 6
+	defaultAnomalyThreshold       = 3.5
+)
+
+// minRelativeScale is the scale used, relative to the median, when the window is constant
+// (MAD and mean absolute deviation are 0), so that a sudden change is still detected.
+const minRelativeScale = 0.05
+
+func withAnomalyDefaults(opts models.AnomalyOpts) models.AnomalyOpts {
+	if opts.Window <= 0 {
+		opts.Window = defaultAnomalyWindow
+	}
+	if opts.MinObservations <= 0 {
+		opts.MinObservations = defaultAnomalyMinObservations
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = defaultAnomalyThreshold
+	}
+	return opts
+}
+
+// robustScore returns the median of the window and the robust z-score of the value, with the
+// scale described in models.PeerStats. It returns ok = false when the score is undefined
+// (window with only zeros), so a new non-zero value is not flagged.
+func robustScore(value float64, window []float64) (median, score float64, ok bool) {
+	median = medianOf(window)
+	deviations := make([]float64, len(window))
+	var sum float64
+	for i, w := range window {
+		deviations[i] = math.Abs(w - median)
+		sum += deviations[i]
+	}
+	scale := 1.4826 * medianOf(deviations)
+	if scale == 0 {
+		scale = 1.2533 * sum / float64(len(window))
+	}
+	if scale == 0 {
+		scale = minRelativeScale * math.Abs(median)
+	}
+	if scale == 0 {
+		return median, 0, false
+	}
+	return median, (value - median) / scale, true
+}
+
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// detectAnomalies compares each month of the period with the collections of the previous
+// opts.Window months. The collections must be sorted by year and month and may include
+// months before the period (used only as window). Rubricas missing from the item summary
+// of a month are considered 0 in that month, so a rubrica that stops being paid may be
+// flagged, but one paid for the first time is not (its window has only zeros).
+func detectAnomalies(agmis []models.AgencyMonthlyInfo, period models.Period, opts models.AnomalyOpts) []models.Anomaly {
+	opts = withAnomalyDefaults(opts)
+	type series struct {
+		metric models.AnomalyMetric
+		item   string
+	}
+	index := func(month, year int) int { return year*12 + month - 1 }
+	values := make(map[series][]float64)
+	var months []int
+	for _, agmi := range agmis {
+		if agmi.Summary == nil {
+			continue
+		}
+		s := agmi.Summary
+		i := len(months)
+		months = append(months, index(agmi.Month, agmi.Year))
+		add := func(key series, v float64) {
+			if values[key] == nil {
+				values[key] = make([]float64, len(agmis))
+			}
+			values[key][i] = v
+		}
+		add(series{metric: models.AnomalyMembers}, float64(s.Count))
+		add(series{metric: models.AnomalyBaseRemuneration}, s.BaseRemuneration.Total)
+		add(series{metric: models.AnomalyOtherRemunerations}, s.OtherRemunerations.Total)
+		for item, v := range s.ItemSummary {
+			add(series{metric: models.AnomalyItem, item: item}, v)
+		}
+	}
+
+	from, to := index(period.FromMonth, period.FromYear), index(period.ToMonth, period.ToYear)
+	var anomalies []models.Anomaly
+	for i, m := range months {
+		if m < from || m > to {
+			continue
+		}
+		// Collected months in the window: [m - Window, m).
+		start := i
+		for start > 0 && months[start-1] >= m-opts.Window {
+			start--
+		}
+		if i-start < opts.MinObservations {
+			continue
+		}
+		for key, v := range values {
+			median, score, ok := robustScore(v[i], v[start:i])
+			if !ok || math.Abs(score) < opts.Threshold {
+				continue
+			}
+			anomalies = append(anomalies, models.Anomaly{
+				AgencyID: agmis[0].AgencyID,
+				Month:    m%12 + 1,
+				Year:     m / 12,
+				Metric:   key.metric,
+				Item:     key.item,
+				Value:    v[i],
+				Median:   median,
+				Score:    score,
+			})
+		}
+	}
+	sort.Slice(anomalies, func(i, j int) bool {
+		a, b := anomalies[i], anomalies[j]
+		if a.Year != b.Year || a.Month != b.Month {
+			return index(a.Month, a.Year) < index(b.Month, b.Year)
+		}
+		if a.Metric != b.Metric {
+			return a.Metric < b.Metric
+		}
+		return a.Item < b.Item
+	})
+	return anomalies
+}
@@ -1,7 +1,12 @@
 package storage
 
 import (
+	"context"
 	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/dadosjusbr/storage/models"
 	"github.com/dadosjusbr/storage/repo/database"
@@ -12,6 +17,8 @@ import (
 type Client struct {
 	Db    database.Interface
 	Cloud file_storage.Interface
+	// Clock returns the current time, used as the end of GetCoverage. time.Now is used if nil.
+	Clock func() time.Time
 }
 
 // NewClient NewClient
@@ -32,7 +39,7 @@ func (c *Client) Close() error {
 func (c *Client) GetStateAgencies(uf string) ([]models.Agency, error) {
 	ags, err := c.Db.GetStateAgencies(uf)
 	if err != nil {
-		return nil, fmt.Errorf("GetStateAgencies() error: %q", err)
+		return nil, fmt.Errorf("GetStateAgencies() error: %w", err)
 	}
 	return ags, err
 }
@@ -41,7 +48,7 @@ func (c *Client) GetStateAgencies(uf string) ([]models.Agency, error) {
 func (c *Client) GetOPJ(group string) ([]models.Agency, error) {
 	ags, err := c.Db.GetOPJ(group)
 	if err != nil {
-		return nil, fmt.Errorf("GetOPJ() error: %q", err)
+		return nil, fmt.Errorf("GetOPJ() error: %w", err)
 	}
 	return ags, err
 }
@@ -50,29 +57,113 @@ func (c *Client) GetOPJ(group string) ([]models.Agency, error) {
 func (c *Client) GetOMA(month int, year int, agency string) (*models.AgencyMonthlyInfo, *models.Agency, error) {
 	agsMR, agencyObj, err := c.Db.GetOMA(month, year, agency)
 	if err != nil {
-		return nil, nil, fmt.Errorf("GetOMA() error: %q", err)
+		return nil, nil, fmt.Errorf("GetOMA() error: %w", err)
 	}
 	return agsMR, agencyObj, nil
 }
 
-// Store stores the Agency Monthly Info stats.
+// Store stores the Agency Monthly Info stats. An identical re-collection only updates the
+// last verified timestamp of the current revision; use StoreRevision to know whether a new
+// revision was created.
 func (c *Client) Store(agmi models.AgencyMonthlyInfo) error {
-	if err := c.Db.Store(agmi); err != nil {
-		return fmt.Errorf("Store() error: %q", err)
+	if _, err := c.storeRevision("Store", agmi); err != nil {
+		return err
 	}
 	return nil
 }
 
+// StoreRevision stores the Agency Monthly Info stats like Store and returns whether a new
+// revision was created: an identical re-collection only updates the last verified timestamp
+// of the current revision.
+func (c *Client) StoreRevision(agmi models.AgencyMonthlyInfo) (bool, error) {
+	return c.storeRevision("StoreRevision", agmi)
+}
+
+func (c *Client) storeRevision(method string, agmi models.AgencyMonthlyInfo) (bool, error) {
+	v := newValidator()
+	v.monthlyInfo("agmi", agmi)
+	if err := c.validate(v); err != nil {
+		return false, fmt.Errorf("%s() error: %w", method, err)
+	}
+	created, err := c.Db.Store(agmi)
+	if err != nil {
+		return false, fmt.Errorf("%s() error: %w", method, err)
+	}
+	return created, nil
+}
+
 func (c *Client) StorePaychecks(p []models.Paycheck, r []models.PaycheckItem) error {
+	v := newValidator()
+	v.paychecks(p, r)
+	if err := c.validate(v); err != nil {
+		return fmt.Errorf("StorePaychecks() error: %w", err)
+	}
 	if err := c.Db.StorePaychecks(p, r); err != nil {
-		return fmt.Errorf("StorePaychecks() error: %q", err)
+		return fmt.Errorf("StorePaychecks() error: %w", err)
+	}
+	return nil
+}
+
+// ReplacePaychecks stores the paychecks and paycheck items replacing the data of the
+// agency-month of the batch: rows of that agency-month that are not in the batch are deleted.
+// All rows must belong to the same agency-month; a batch spanning several agency-months is
+// rejected with ErrInvalidInput, so call it once per agency-month.
+func (c *Client) ReplacePaychecks(p []models.Paycheck, r []models.PaycheckItem) (*models.PaycheckStoreReport, error) {
+	v := newValidator()
+	v.paychecks(p, r)
+	if err := c.validate(v); err != nil {
+		return nil, fmt.Errorf("ReplacePaychecks() error: %w", err)
+	}
+	report, err := c.Db.ReplacePaychecks(p, r)
+	if err != nil {
+		return nil, fmt.Errorf("ReplacePaychecks() error: %w", err)
+	}
+	return report, nil
+}
+
+// BulkStorePaychecks stores paychecks, paycheck items and retroactive payments using COPY
+// (or batched INSERTs, if the database driver does not support COPY).
+// It should be preferred over StorePaychecks for big agencies.
+func (c *Client) BulkStorePaychecks(p []models.Paycheck, r []models.PaycheckItem, rp []models.RetroactivePayments) error {
+	v := newValidator()
+	v.paychecks(p, r)
+	v.retroactivePayments(rp)
+	if err := c.validate(v); err != nil {
+		return fmt.Errorf("BulkStorePaychecks() error: %w", err)
+	}
+	if err := c.Db.BulkStorePaychecks(p, r, rp); err != nil {
+		return fmt.Errorf("BulkStorePaychecks() error: %w", err)
 	}
 	return nil
 }
 
 func (c *Client) StoreRemunerations(remu models.Remunerations) error {
+	v := newValidator()
+	v.remunerations("remunerations", remu)
+	if err := c.validate(v); err != nil {
+		return fmt.Errorf("StoreRemunerations() error: %w", err)
+	}
 	if err := c.Db.StoreRemunerations(remu); err != nil {
-		return fmt.Errorf("StoreRemunerations() error: %q", err)
+		return fmt.Errorf("StoreRemunerations() error: %w", err)
+	}
+	return nil
+}
+
+// StoreCollection stores the monthly info, paychecks, paycheck items, retroactive payments
+// and remunerations zip metadata of a collection in a single transaction (all-or-nothing).
+func (c *Client) StoreCollection(col models.Collection) error {
+	v := newValidator()
+	v.monthlyInfo("monthly_info", col.MonthlyInfo)
+	v.paychecks(col.Paychecks, col.PaycheckItems)
+	v.retroactivePayments(col.RetroactivePayments)
+	if col.Remunerations != nil {
+		v.remunerations("remunerations", *col.Remunerations)
+	}
+	if err := c.validate(v); err != nil {
+		return fmt.Errorf("StoreCollection() error: %w", err)
+	}
+	if err := c.Db.StoreCollection(col); err != nil {
+		return fmt.Errorf("StoreCollection() error: %w", err)
 	}
 	return nil
 }
@@ -81,7 +172,7 @@ func (c *Client) StoreRemunerations(remu models.Remunerations) error {
 func (c *Client) GetAgenciesCount() (int, error) {
 	count, err := c.Db.GetAgenciesCount()
 	if err != nil {
-		return count, fmt.Errorf("GetAgenciesCount() error: %q", err)
+		return count, fmt.Errorf("GetAgenciesCount() error: %w", err)
 	}
 	return count, nil
 }
@@ -90,7 +181,7 @@ func (c *Client) GetAgenciesCount() (int, error) {
 func (c *Client) GetNumberOfMonthsCollected() (int, error) {
 	count, err := c.Db.GetNumberOfMonthsCollected()
 	if err != nil {
-		return count, fmt.Errorf("GetNumberOfMonthsCollected() error: %q", err)
+		return count, fmt.Errorf("GetNumberOfMonthsCollected() error: %w", err)
 	}
 	return count, nil
 }
@@ -98,7 +189,7 @@ func (c *Client) GetNumberOfMonthsCollected() (int, error) {
 func (c *Client) GetNumberOfPaychecksCollected() (int, error) {
 	count, err := c.Db.GetNumberOfPaychecksCollected()
 	if err != nil {
-		return count, fmt.Errorf("GetNumberOfPaychecksCollected() error: %q", err)
+		return count, fmt.Errorf("GetNumberOfPaychecksCollected() error: %w", err)
 	}
 	return count, nil
 }
@@ -107,7 +198,7 @@ func (c *Client) GetNumberOfPaychecksCollected() (int, error) {
 func (c *Client) GetLastDateWithMonthlyInfo() (int, int, error) {
 	month, year, err := c.Db.GetLastDateWithMonthlyInfo()
 	if err != nil {
-		return 0, 0, fmt.Errorf("GetLastDateWithMonthlyInfo() error: %q", err)
+		return 0, 0, fmt.Errorf("GetLastDateWithMonthlyInfo() error: %w", err)
 	}
 	return month, year, nil
 }
@@ -116,21 +207,38 @@ func (c *Client) GetLastDateWithMonthlyInfo() (int, int, error) {
 func (c *Client) GetFirstDateWithMonthlyInfo() (int, int, error) {
 	month, year, err := c.Db.GetFirstDateWithMonthlyInfo()
 	if err != nil {
-		return 0, 0, fmt.Errorf("GetFirstDateWithMonthlyInfo() error: %q", err)
+		return 0, 0, fmt.Errorf("GetFirstDateWithMonthlyInfo() error: %w", err)
 	}
 	return month, year, nil
 }
 
-func (c *Client) GetAnnualSummary(agency string) ([]models.AnnualSummary, error) {
-	summary, err := c.Db.GetAnnualSummary(agency)
+func (c *Client) GetAnnualSummary(agency string, opts ...models.AggregationOpts) ([]models.AnnualSummary, error) {
+	summary, err := c.Db.GetAnnualSummary(agency, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting annual data from database: %w", err)
+	}
+	// Os pacotes anuais são buscados no catálogo de pacotes. Apenas os anos que
+	// não estão no catálogo são consultados diretamente no file storage.
+	scope := models.PackageScopeAnnual
+	pkgs, err := c.Db.GetPackages(models.PackageFilterOpts{AgencyID: &agency, Scope: &scope})
 	if err != nil {
-		return nil, fmt.Errorf("Error getting annual data from database: %q", err)
+		return nil, fmt.Errorf("Error getting annual packages from database: %w", err)
+	}
+	pkgsByYear := make(map[int]models.Backup)
+	for _, pkg := range pkgs {
+		if pkg.Year != nil {
+			pkgsByYear[*pkg.Year] = pkg.Package
+		}
 	}
 	for i := range summary {
+		if pkg, ok := pkgsByYear[summary[i].Year]; ok {
+			summary[i].Package = &pkg
+			continue
+		}
 		dstKey := fmt.Sprintf("%s/datapackage/%s-%d.zip", agency, agency, summary[i].Year)
 		pkg, err := c.Cloud.GetFile(dstKey)
 		if err != nil {
-			return nil, fmt.Errorf("Error getting annual data from file storage: %q", err)
+			return nil, fmt.Errorf("Error getting annual data from file storage: %w", err)
 		}
 		summary[i].Package = pkg
 	}
@@ -155,10 +263,367 @@ func (c *Client) GetAllAgencyCollection(agency string) ([]models.AgencyMonthlyIn
 	return collections, nil
 }
 
-func (c *Client) GetAveragePerCapita(agency string, year int) (*models.PerCapitaData, error) {
-	avg, err := c.Db.GetAveragePerCapita(agency, year)
+func (c *Client) GetAveragePerCapita(agency string, year int, opts ...models.AggregationOpts) (*models.PerCapitaData, error) {
+	avg, err := c.Db.GetAveragePerCapita(agency, year, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("GetAveragePerCapita() error: %w", err)
 	}
 	return avg, nil
 }
+
+// DeleteMonthlyData remove completamente os dados de um órgão em um mês/ano: todas as
+// revisões da coleta, contracheques, remunerações, retroativos, zips de remunerações,
+// resumo das rubricas, pacotes mensais e anomalias.
+// Se removeFiles for verdadeiro, os arquivos relacionados (backups, pacotes e zips)
+// também são removidos do file storage. A remoção dos arquivos acontece depois
+// da remoção no banco de dados, que é transacional.
+func (c *Client) DeleteMonthlyData(agency string, month, year int, removeFiles bool) (*models.DeletionReport, error) {
+	report, err := c.Db.DeleteMonthlyData(agency, month, year)
+	if err != nil {
+		return nil, fmt.Errorf("DeleteMonthlyData() error: %w", err)
+	}
+	if !removeFiles {
+		return report, nil
+	}
+	for _, fileURL := range report.Files {
+		key, err := fileKey(fileURL)
+		if err != nil {
+			return report, fmt.Errorf("DeleteMonthlyData() error: %w", err)
+		}
+		if err := c.Cloud.DeleteFile(key); err != nil {
+			return report, fmt.Errorf("DeleteMonthlyData() error removing file (%s): %w", fileURL, err)
+		}
+		report.RemovedFiles = append(report.RemovedFiles, fileURL)
+	}
+	return report, nil
+}
+
+// fileKey extrai a chave de um arquivo no file storage a partir da sua URL.
+// Ex.: https://dadosjusbr-public.s3.amazonaws.com/tjba/backups/tjba-2022-12.zip -> tjba/backups/tjba-2022-12.zip
+func fileKey(fileURL string) (string, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return "", models.NewError(models.ErrInvalidInput, fmt.Errorf("error parsing file url (%s): %w", fileURL, err))
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	if key == "" {
+		return "", models.NewError(models.ErrInvalidInput, fmt.Errorf("invalid file url (%s)", fileURL))
+	}
+	return key, nil
+}
+
+// StorePackage registra um datapackage no catálogo de pacotes.
+func (c *Client) StorePackage(pkg models.Package) error {
+	if err := c.Db.StorePackage(pkg); err != nil {
+		return fmt.Errorf("StorePackage() error: %w", err)
+	}
+	return nil
+}
+
+// UploadPackage envia o datapackage em srcPath para o file storage, com a chave de
+// pkg, e o registra no catálogo de pacotes.
+func (c *Client) UploadPackage(srcPath string, pkg models.Package) (*models.Package, error) {
+	bkp, err := c.Cloud.UploadFile(srcPath, pkg.Key)
+	if err != nil {
+		return nil, fmt.Errorf("UploadPackage() error: %w", err)
+	}
+	pkg.Package = *bkp
+	pkg.Scope = pkg.InferScope()
+	if err := c.Db.StorePackage(pkg); err != nil {
+		return nil, fmt.Errorf("UploadPackage() error: %w", err)
+	}
+	return &pkg, nil
+}
+
+// GetPackages lista os datapackages do catálogo de acordo com o filtro.
+func (c *Client) GetPackages(opts models.PackageFilterOpts) ([]models.Package, error) {
+	pkgs, err := c.Db.GetPackages(opts)
+	if err != nil {
+		return nil, fmt.Errorf("GetPackages() error: %w", err)
+	}
+	return pkgs, nil
+}
+
+// ComputeSummary computes the summary of an agency-month from its stored paychecks and paycheck items.
+func (c *Client) ComputeSummary(agency string, month, year int) (*models.Summary, error) {
+	summary, err := c.Db.ComputeSummary(agency, month, year)
+	if err != nil {
+		return nil, fmt.Errorf("ComputeSummary() error: %w", err)
+	}
+	return summary, nil
+}
+
+// VerifySummary compares the stored summary of an agency-month (sent by the crawler) with
+// the summary computed from its stored paychecks and reports the discrepancies.
+func (c *Client) VerifySummary(agency string, month, year int) (*models.SummaryVerification, error) {
+	agmi, _, err := c.Db.GetOMA(month, year, agency)
+	if err != nil {
+		return nil, fmt.Errorf("VerifySummary() error getting stored summary: %w", err)
+	}
+	computed, err := c.Db.ComputeSummary(agency, month, year)
+	if err != nil {
+		return nil, fmt.Errorf("VerifySummary() error computing summary: %w", err)
+	}
+	stored := agmi.Summary
+	if stored == nil {
+		stored = &models.Summary{}
+	}
+	return &models.SummaryVerification{
+		AgencyID:      agmi.AgencyID,
+		Month:         month,
+		Year:          year,
+		Stored:        stored,
+		Computed:      computed,
+		Discrepancies: compareSummaries(*stored, *computed),
+	}, nil
+}
+
+// StoreRemunerationCaps stores (or updates) the remuneration caps. Each cap is in effect
+// from its month on, until the month of the next cap.
+func (c *Client) StoreRemunerationCaps(caps []models.RemunerationCap) error {
+	v := newValidator()
+	v.remunerationCaps(caps)
+	if err := c.validate(v); err != nil {
+		return fmt.Errorf("StoreRemunerationCaps() error: %w", err)
+	}
+	if err := c.Db.StoreRemunerationCaps(caps); err != nil {
+		return fmt.Errorf("StoreRemunerationCaps() error: %w", err)
+	}
+	return nil
+}
+
+// GetCapExceedances reports, for each agency-month of the period, the members whose
+// remuneration exceeded the cap in effect, the total and average excess and the rubricas
+// paid to them. All agencies are considered if none is given.
+func (c *Client) GetCapExceedances(agencies []models.Agency, period models.Period) ([]models.CapExceedance, error) {
+	exceedances, err := c.Db.GetCapExceedances(agencies, period)
+	if err != nil {
+		return nil, fmt.Errorf("GetCapExceedances() error: %w", err)
+	}
+	return exceedances, nil
+}
+
+// StorePriceIndex stores (or updates) the monthly price index series used to deflate the
+// aggregations (see models.AggregationOpts).
+func (c *Client) StorePriceIndex(series []models.PriceIndex) error {
+	v := newValidator()
+	v.priceIndex(series)
+	if err := c.validate(v); err != nil {
+		return fmt.Errorf("StorePriceIndex() error: %w", err)
+	}
+	if err := c.Db.StorePriceIndex(series); err != nil {
+		return fmt.Errorf("StorePriceIndex() error: %w", err)
+	}
+	return nil
+}
+
+// LoadPriceIndex reads the monthly price index series from a CSV file (see parsePriceIndex)
+// and stores it.
+func (c *Client) LoadPriceIndex(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return models.NewError(models.ErrInvalidInput, fmt.Errorf("LoadPriceIndex() error opening file: %w", err))
+	}
+	defer f.Close()
+	series, err := parsePriceIndex(f)
+	if err != nil {
+		return fmt.Errorf("LoadPriceIndex() error: %w", err)
+	}
+	if err := c.StorePriceIndex(series); err != nil {
+		return fmt.Errorf("LoadPriceIndex() error: %w", err)
+	}
+	return nil
+}
+
+// DetectAnomalies flags the months of the period in which the member count, the base or
+// other remunerations total or a rubrica total of the agency is unusual, using a robust
+// z-score against the previous months (see models.AnomalyOpts).
+func (c *Client) DetectAnomalies(agencyID string, period models.Period, opts ...models.AnomalyOpts) ([]models.Anomaly, error) {
+	if err := period.Validate(); err != nil {
+		return nil, fmt.Errorf("DetectAnomalies() error: %w", err)
+	}
+	var o models.AnomalyOpts
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	o = withAnomalyDefaults(o)
+	// The window of the first months of the period is before it.
+	from := models.LastMonths(period.FromMonth, period.FromYear, o.Window+1)
+	agmis, err := c.Db.GetMonthlyInfoInPeriod([]models.Agency{{ID: agencyID}}, models.Period{
+		FromMonth: from.FromMonth,
+		FromYear:  from.FromYear,
+		ToMonth:   period.ToMonth,
+		ToYear:    period.ToYear,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("DetectAnomalies() error: %w", err)
+	}
+	return detectAnomalies(agmis[agencyID], period, o), nil
+}
+
+// StoreAnomalies replaces the stored anomalies of the agency in the period, so they can be
+// queried with GetAnomalies (e.g. alongside GetOMA).
+func (c *Client) StoreAnomalies(agencyID string, period models.Period, anomalies []models.Anomaly) error {
+	v := newValidator()
+	v.anomalies(agencyID, period, anomalies)
+	if err := c.validate(v); err != nil {
+		return fmt.Errorf("StoreAnomalies() error: %w", err)
+	}
+	if err := c.Db.StoreAnomalies(agencyID, period, anomalies); err != nil {
+		return fmt.Errorf("StoreAnomalies() error: %w", err)
+	}
+	return nil
+}
+
+// GetAnomalies returns the stored anomalies of the agency in the period.
+func (c *Client) GetAnomalies(agencyID string, period models.Period) ([]models.Anomaly, error) {
+	anomalies, err := c.Db.GetAnomalies(agencyID, period)
+	if err != nil {
+		return nil, fmt.Errorf("GetAnomalies() error: %w", err)
+	}
+	return anomalies, nil
+}
+
+// GetOutlierPaychecks returns the paychecks of an agency in a year (or month) whose
+// remuneration, or the total of one of the given rubricas, is far above the paychecks of the
+// members with the same normalized role in the month (see StoreRoleNormalizations).
+func (c *Client) GetOutlierPaychecks(opts models.OutlierOpts) ([]models.OutlierPaycheck, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("GetOutlierPaychecks() error: %w", err)
+	}
+	outliers, err := c.Db.GetOutlierPaychecks(opts)
+	if err != nil {
+		return nil, fmt.Errorf("GetOutlierPaychecks() error: %w", err)
+	}
+	return outliers, nil
+}
+
+// GetCoverage returns, for each agency (all of them if none is given), which months from
+// the first monitored month to the current month have a current collection, paychecks,
+// a remuneration zip or inconsistent remunerations, and which are missing or not published
+// by the agency according to its collecting statuses (see models.AgencyCoverage).
+func (c *Client) GetCoverage(agencies []models.Agency) ([]models.AgencyCoverage, error) {
+	now := time.Now()
+	if c.Clock != nil {
+		now = c.Clock()
+	}
+	coverage, err := c.Db.GetCoverage(agencies, models.MonthYear{Month: int(now.Month()), Year: now.Year()})
+	if err != nil {
+		return nil, fmt.Errorf("GetCoverage() error: %w", err)
+	}
+	return coverage, nil
+}
+
+// GetItemTrends returns the rubricas most paid in the period, by total or by number of
+// recipients, across all agencies or the agencies of a group, with their monthly series.
+func (c *Client) GetItemTrends(opts models.ItemRankingOpts, aggOpts ...models.AggregationOpts) ([]models.ItemTrend, error) {
+	trends, err := c.Db.GetItemTrends(opts, aggOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("GetItemTrends() error: %w", err)
+	}
+	return trends, nil
+}
+
+// StoreRoleNormalizations stores (or updates) the patterns used to group role variants,
+// such as "JUIZ DE DIREITO" and "Juiz de Direito - Entrância Final", into a normalized role.
+func (c *Client) StoreRoleNormalizations(rules []models.RoleNormalization) error {
+	v := newValidator()
+	v.roleNormalizations(rules)
+	if err := c.validate(v); err != nil {
+		return fmt.Errorf("StoreRoleNormalizations() error: %w", err)
+	}
+	if err := c.Db.StoreRoleNormalizations(rules); err != nil {
+		return fmt.Errorf("StoreRoleNormalizations() error: %w", err)
+	}
+	return nil
+}
+
+// GetRemunerationsByRole returns the totals and headcount of the paychecks of an agency in
+// the period, grouped by normalized role.
+func (c *Client) GetRemunerationsByRole(agencyID string, period models.Period, opts ...models.AggregationOpts) ([]models.RemunerationGroup, error) {
+	groups, err := c.Db.GetRemunerationsByRole(agencyID, period, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("GetRemunerationsByRole() error: %w", err)
+	}
+	return groups, nil
+}
+
+// GetRemunerationsByWorkplace returns the totals and headcount of the paychecks of an agency
+// in the period, grouped by workplace.
+func (c *Client) GetRemunerationsByWorkplace(agencyID string, period models.Period, opts ...models.AggregationOpts) ([]models.RemunerationGroup, error) {
+	groups, err := c.Db.GetRemunerationsByWorkplace(agencyID, period, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("GetRemunerationsByWorkplace() error: %w", err)
+	}
+	return groups, nil
+}
+
+// GetRanking ranks the agencies by a metric (per-capita remuneration, benefits share or one
+// of the transparency indexes) in a year or month, from the highest value to the lowest.
+// The yearly per-capita values only consider the members with paychecks in more than one
+// month of the year, while the monthly ones consider every member of the summary. Unknown
+// metrics and invalid months are rejected with ErrInvalidInput.
+func (c *Client) GetRanking(opts models.RankingOpts) ([]models.RankingEntry, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("GetRanking() error: %w", err)
+	}
+	entries, err := c.Db.GetRanking(opts)
+	if err != nil {
+		return nil, fmt.Errorf("GetRanking() error: %w", err)
+	}
+	return entries, nil
+}
+
+// Health checks whether the storage dependencies are usable: the database connection, the
+// schema migration level, the materialized views and the file storage bucket. It always
+// returns the per-component status; the error wraps ErrUnavailable if any component failed.
+func (c *Client) Health(ctx context.Context) (*models.Health, error) {
+	health := &models.Health{Status: models.HealthStatusOK, CheckedAt: time.Now()}
+	check := func(name string, f func() error) {
+		start := time.Now()
+		err := f()
+		component := models.ComponentHealth{Name: name, Status: models.HealthStatusOK, Latency: time.Since(start)}
+		if err != nil {
+			component.Status = models.HealthStatusFailed
+			component.Error = err.Error()
+			health.Status = models.HealthStatusFailed
+		}
+		health.Components = append(health.Components, component)
+	}
+	check(models.HealthComponentDatabase, func() error {
+		return c.Db.Ping(ctx)
+	})
+	check(models.HealthComponentSchema, func() error {
+		version, err := c.Db.GetSchemaVersion(ctx)
+		if err != nil {
+			return err
+		}
+		if version < database.SchemaVersion {
+			return fmt.Errorf("schema version %d is older than the expected version %d", version, database.SchemaVersion)
+		}
+		return nil
+	})
+	check(models.HealthComponentMaterializedViews, func() error {
+		missing, err := c.Db.GetMissingViews(ctx)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("missing materialized views: %s", strings.Join(missing, ", "))
+		}
+		return nil
+	})
+	check(models.HealthComponentFileStorage, func() error {
+		return c.Cloud.Ping(ctx)
+	})
+	if health.Status != models.HealthStatusOK {
+		var failed []string
+		for _, component := range health.Components {
+			if component.Status != models.HealthStatusOK {
+				failed = append(failed, component.Name)
+			}
+		}
+		return health, models.NewError(models.ErrUnavailable, fmt.Errorf("Health() error: unhealthy components: %s", strings.Join(failed, ", ")))
+	}
+	return health, nil
+}
@@ -1,9 +1,13 @@
 package storage_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/dadosjusbr/storage"
 	"github.com/dadosjusbr/storage/models"
@@ -67,7 +71,7 @@ func (getStateAgencies) testWhenRepositoryReturnError(t *testing.T) {
 
 	client, err := storage.NewClient(dbMock, fsMock)
 	returnedAgencies, err := client.GetStateAgencies("SP")
-	expectedErr := errors.New(fmt.Sprintf("GetStateAgencies() error: \"%s\"", repoErr.Error()))
+	expectedErr := fmt.Errorf("GetStateAgencies() error: %w", repoErr)
 
 	assert.Equal(t, expectedErr, err)
 	assert.Nil(t, returnedAgencies)
@@ -142,7 +146,7 @@ func (getOPJ) testWhenRepositoryReturnError(t *testing.T) {
 
 	client, err := storage.NewClient(dbMock, fsMock)
 	returnedAgencies, err := client.GetOPJ("Estadual")
-	expectedErr := errors.New(fmt.Sprintf("GetOPJ() error: \"%s\"", repoErr.Error()))
+	expectedErr := fmt.Errorf("GetOPJ() error: %w", repoErr)
 
 	assert.Equal(t, expectedErr, err)
 	assert.Nil(t, returnedAgencies)
@@ -201,7 +205,7 @@ func (getFirstDateWithMonthlyInfo) testWhenRepositoryReturnError(t *testing.T) {
 
 	client, err := storage.NewClient(dbMock, fsMock)
 	month, year, err := client.GetFirstDateWithMonthlyInfo()
-	expectedErr := errors.New(fmt.Sprintf("GetFirstDateWithMonthlyInfo() error: \"%s\"", repoErr.Error()))
+	expectedErr := fmt.Errorf("GetFirstDateWithMonthlyInfo() error: %w", repoErr)
 
 	assert.Equal(t, expectedErr, err)
 	assert.Equal(t, 0, month)
@@ -244,7 +248,7 @@ func (getLastDateWithMonthlyInfo) testWhenRepositoryReturnError(t *testing.T) {
 
 	client, err := storage.NewClient(dbMock, fsMock)
 	month, year, err := client.GetLastDateWithMonthlyInfo()
-	expectedErr := errors.New(fmt.Sprintf("GetLastDateWithMonthlyInfo() error: \"%s\"", repoErr.Error()))
+	expectedErr := fmt.Errorf("GetLastDateWithMonthlyInfo() error: %w", repoErr)
 
 	assert.Equal(t, expectedErr, err)
 	assert.Equal(t, 0, month)
@@ -287,7 +291,7 @@ func (getNumberOfMonthsCollected) testWhenRepositoryReturnError(t *testing.T) {
 
 	client, err := storage.NewClient(dbMock, fsMock)
 	returnedMonths, err := client.GetNumberOfMonthsCollected()
-	expectedErr := errors.New(fmt.Sprintf("GetNumberOfMonthsCollected() error: \"%s\"", repoErr.Error()))
+	expectedErr := fmt.Errorf("GetNumberOfMonthsCollected() error: %w", repoErr)
 
 	assert.Equal(t, expectedErr, err)
 	assert.Equal(t, 0, returnedMonths)
@@ -329,7 +333,7 @@ func (getAgenciesCount) testWhenRepositoryReturnError(t *testing.T) {
 
 	client, err := storage.NewClient(dbMock, fsMock)
 	returnedAgenciesCount, err := client.GetAgenciesCount()
-	expectedErr := errors.New(fmt.Sprintf("GetAgenciesCount() error: \"%s\"", repoErr.Error()))
+	expectedErr := fmt.Errorf("GetAgenciesCount() error: %w", repoErr)
 
 	assert.Equal(t, expectedErr, err)
 	assert.Equal(t, 0, returnedAgenciesCount)
@@ -339,6 +343,7 @@ func TestGetOMA(t *testing.T) {
 	tests := getOMA{}
 	t.Run("Test GetOMA when repository return OMA", tests.testWhenRepositoryReturnOMA)
 	t.Run("Test GetOMA when repository return error", tests.testWhenRepositoryReturnError)
+	t.Run("Test GetOMA when data does not exist", tests.testWhenDataNotExists)
 }
 
 type getOMA struct{}
@@ -380,13 +385,32 @@ func (getOMA) testWhenRepositoryReturnError(t *testing.T) {
 
 	client, err := storage.NewClient(dbMock, fsMock)
 	returnedOMA, returnedAgency, err := client.GetOMA(1, 2020, "tjsp")
-	expectedErr := errors.New(fmt.Sprintf("GetOMA() error: \"%s\"", repoErr.Error()))
+	expectedErr := fmt.Errorf("GetOMA() error: %w", repoErr)
 
 	assert.Equal(t, expectedErr, err)
 	assert.Nil(t, returnedOMA)
 	assert.Nil(t, returnedAgency)
 }
 
+func (getOMA) testWhenDataNotExists(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	repoErr := models.NewError(models.ErrNotFound, errors.New("there is no data with this parameters"))
+	dbMock.EXPECT().GetOMA(1, 2020, "tjsp").Return(nil, nil, repoErr)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	_, _, err = client.GetOMA(1, 2020, "tjsp")
+
+	var storageErr *models.Error
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.ErrorAs(t, err, &storageErr)
+	assert.Equal(t, storage.ErrNotFound, storageErr.Kind)
+	assert.NotErrorIs(t, err, storage.ErrUnavailable)
+}
+
 func TestStoreRemunerations(t *testing.T) {
 	tests := storeRemunerations{}
 	t.Run("Test StoreRemunerations when repository store remunerations", tests.testWhenRepositoryStoreRemunerations)
@@ -409,6 +433,7 @@ func (storeRemunerations) testWhenRepositoryStoreRemunerations(t *testing.T) {
 		NumOther:     10,
 		ZipUrl:       "https://dadosjusbr-public.s3.amazonaws.com/tjsp/remunerations/tjsp-2020-01.zip",
 	}
+	dbMock.EXPECT().GetAgency("tjsp").Return(&models.Agency{ID: "tjsp"}, nil)
 	dbMock.EXPECT().StoreRemunerations(remunerations).Return(nil)
 	dbMock.EXPECT().Connect().Return(nil)
 
@@ -434,12 +459,13 @@ func (storeRemunerations) testWhenRepositoryReturnError(t *testing.T) {
 	}
 
 	repoErr := errors.New("error storing remunerations")
+	dbMock.EXPECT().GetAgency("tjsp").Return(&models.Agency{ID: "tjsp"}, nil)
 	dbMock.EXPECT().StoreRemunerations(remunerations).Return(repoErr)
 	dbMock.EXPECT().Connect().Return(nil)
 
 	client, err := storage.NewClient(dbMock, fsMock)
 	err = client.StoreRemunerations(remunerations)
-	expectedErr := errors.New(fmt.Sprintf("StoreRemunerations() error: \"%s\"", repoErr.Error()))
+	expectedErr := fmt.Errorf("StoreRemunerations() error: %w", repoErr)
 
 	assert.Equal(t, expectedErr, err)
 }
@@ -448,6 +474,8 @@ func TestStore(t *testing.T) {
 	tests := store{}
 	t.Run("Test Store when repository store data", tests.testWhenRepositoryStoreData)
 	t.Run("Test Store when database connection fails", tests.testWhenRepositoryReturnError)
+	t.Run("Test Store when data is invalid", tests.testWhenDataIsInvalid)
+	t.Run("Test StoreRevision when the collection is identical", tests.testRevisionWhenCollectionIsIdentical)
 }
 
 type store struct{}
@@ -463,7 +491,8 @@ func (store) testWhenRepositoryStoreData(t *testing.T) {
 		Year:              2020,
 		CrawlingTimestamp: timestamppb.Now(),
 	}
-	dbMock.EXPECT().Store(agmi).Return(nil)
+	dbMock.EXPECT().GetAgency("tjsp").Return(&models.Agency{ID: "tjsp"}, nil)
+	dbMock.EXPECT().Store(agmi).Return(true, nil)
 	dbMock.EXPECT().Connect().Return(nil)
 
 	client, err := storage.NewClient(dbMock, fsMock)
@@ -473,18 +502,1199 @@ func (store) testWhenRepositoryStoreData(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func (store) testRevisionWhenCollectionIsIdentical(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	agmi := models.AgencyMonthlyInfo{
+		AgencyID:          "tjsp",
+		Month:             1,
+		Year:              2020,
+		CrawlingTimestamp: timestamppb.Now(),
+	}
+	dbMock.EXPECT().GetAgency("tjsp").Return(&models.Agency{ID: "tjsp"}, nil)
+	dbMock.EXPECT().Store(agmi).Return(false, nil)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	created, err := client.StoreRevision(agmi)
+
+	assert.Nil(t, err)
+	assert.False(t, created)
+}
+
 func (store) testWhenRepositoryReturnError(t *testing.T) {
 	mockCrl := gomock.NewController(t)
 	dbMock := database.NewMockInterface(mockCrl)
 	fsMock := file_storage.NewMockInterface(mockCrl)
 
+	agmi := models.AgencyMonthlyInfo{AgencyID: "tjsp", Month: 1, Year: 2020}
 	repoErr := errors.New("error storing data")
-	dbMock.EXPECT().Store(models.AgencyMonthlyInfo{}).Return(repoErr)
+	dbMock.EXPECT().GetAgency("tjsp").Return(&models.Agency{ID: "tjsp"}, nil)
+	dbMock.EXPECT().Store(agmi).Return(false, repoErr)
 	dbMock.EXPECT().Connect().Return(nil)
 
 	client, err := storage.NewClient(dbMock, fsMock)
-	err = client.Store(models.AgencyMonthlyInfo{})
+	err = client.Store(agmi)
 
-	expectedErr := errors.New(fmt.Sprintf("Store() error: \"%s\"", repoErr.Error()))
+	expectedErr := fmt.Errorf("Store() error: %w", repoErr)
 	assert.Equal(t, expectedErr, err)
 }
+
+func (store) testWhenDataIsInvalid(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	agmi := models.AgencyMonthlyInfo{
+		AgencyID: "tjxx",
+		Month:    13,
+		Year:     2020,
+		Summary:  &models.Summary{Count: -1},
+	}
+	dbMock.EXPECT().GetAgency("tjxx").Return(nil, models.NewError(models.ErrNotFound, errors.New("record not found")))
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.Store(agmi)
+
+	var validationErr *models.ValidationError
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []models.FieldError{
+		{Field: "agmi.month", Message: "must be between 1 and 12, got 13"},
+		{Field: "agmi.summary.membros", Message: "cannot be negative, got -1"},
+		{Field: "agmi.aid", Message: "agency tjxx does not exist"},
+	}, validationErr.Errors)
+}
+
+func TestStorePaychecks(t *testing.T) {
+	tests := storePaychecks{}
+	t.Run("Test StorePaychecks when data is OK", tests.testWhenDataIsOK)
+	t.Run("Test StorePaychecks when batch is inconsistent", tests.testWhenBatchIsInconsistent)
+	t.Run("Test StorePaychecks when agency lookup fails", tests.testWhenAgencyLookupFails)
+}
+
+type storePaychecks struct{}
+
+func (storePaychecks) testWhenDataIsOK(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	p := []models.Paycheck{
+		{ID: 1, Agency: "tjsp", Month: 1, Year: 2020},
+		{ID: 2, Agency: "tjsp", Month: 1, Year: 2020},
+	}
+	pi := []models.PaycheckItem{
+		{ID: 1, PaycheckID: 1, Agency: "tjsp", Month: 1, Year: 2020},
+		{ID: 1, PaycheckID: 2, Agency: "tjsp", Month: 1, Year: 2020},
+	}
+	dbMock.EXPECT().GetAgency("tjsp").Return(&models.Agency{ID: "tjsp"}, nil)
+	dbMock.EXPECT().StorePaychecks(p, pi).Return(nil)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.StorePaychecks(p, pi)
+
+	assert.Nil(t, err)
+}
+
+func (storePaychecks) testWhenBatchIsInconsistent(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	p := []models.Paycheck{
+		{ID: 1, Agency: "tjsp", Month: 1, Year: 2020},
+		{ID: 1, Agency: "tjba", Month: 1, Year: 2020},
+	}
+	pi := []models.PaycheckItem{
+		{ID: 1, PaycheckID: 3, Agency: "tjsp", Month: 1, Year: 2020},
+	}
+	dbMock.EXPECT().GetAgency("tjba").Return(&models.Agency{ID: "tjba"}, nil)
+	dbMock.EXPECT().GetAgency("tjsp").Return(&models.Agency{ID: "tjsp"}, nil)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.StorePaychecks(p, pi)
+
+	var validationErr *models.ValidationError
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []models.FieldError{
+		{Field: "paychecks[1]", Message: "belongs to tjba/01/2020, but the write is for tjsp/01/2020"},
+		{Field: "paychecks[1].id", Message: "duplicated paycheck id 1"},
+		{Field: "paycheck_items[0].id_contracheque", Message: "references paycheck 3, which is not in the write"},
+	}, validationErr.Errors)
+}
+
+func (storePaychecks) testWhenAgencyLookupFails(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	p := []models.Paycheck{{ID: 1, Agency: "tjsp", Month: 1, Year: 2020}}
+	dbErr := models.NewError(models.ErrUnavailable, errors.New("connection refused"))
+	dbMock.EXPECT().GetAgency("tjsp").Return(nil, dbErr)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.StorePaychecks(p, nil)
+
+	assert.ErrorIs(t, err, storage.ErrUnavailable)
+	assert.NotErrorIs(t, err, storage.ErrInvalidInput)
+}
+
+func TestDeleteMonthlyData(t *testing.T) {
+	tests := deleteMonthlyData{}
+	t.Run("Test DeleteMonthlyData without removing files", tests.testWhenFilesAreKept)
+	t.Run("Test DeleteMonthlyData removing files", tests.testWhenFilesAreRemoved)
+	t.Run("Test DeleteMonthlyData when file storage return error", tests.testWhenFileStorageReturnError)
+	t.Run("Test DeleteMonthlyData when repository return error", tests.testWhenRepositoryReturnError)
+}
+
+type deleteMonthlyData struct{}
+
+func (deleteMonthlyData) report() *models.DeletionReport {
+	return &models.DeletionReport{
+		AgencyID:    "tjba",
+		Month:       12,
+		Year:        2022,
+		Collections: 2,
+		Paychecks:   10,
+		Files: []string{
+			"https://dadosjusbr-public.s3.amazonaws.com/tjba/backups/tjba-2022-12.zip",
+			"https://dadosjusbr-public.s3.amazonaws.com/tjba/datapackage/tjba-2022-12.zip",
+		},
+	}
+}
+
+func (d deleteMonthlyData) testWhenFilesAreKept(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	dbMock.EXPECT().DeleteMonthlyData("tjba", 12, 2022).Return(d.report(), nil)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	report, err := client.DeleteMonthlyData("tjba", 12, 2022, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, d.report(), report)
+	assert.Empty(t, report.RemovedFiles)
+}
+
+func (d deleteMonthlyData) testWhenFilesAreRemoved(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	dbMock.EXPECT().DeleteMonthlyData("tjba", 12, 2022).Return(d.report(), nil)
+	dbMock.EXPECT().Connect().Return(nil)
+	fsMock.EXPECT().DeleteFile("tjba/backups/tjba-2022-12.zip").Return(nil)
+	fsMock.EXPECT().DeleteFile("tjba/datapackage/tjba-2022-12.zip").Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	report, err := client.DeleteMonthlyData("tjba", 12, 2022, true)
+
+	assert.Nil(t, err)
+	assert.Equal(t, d.report().Files, report.RemovedFiles)
+}
+
+func (d deleteMonthlyData) testWhenFileStorageReturnError(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	fsErr := errors.New("error deleting file")
+	dbMock.EXPECT().DeleteMonthlyData("tjba", 12, 2022).Return(d.report(), nil)
+	dbMock.EXPECT().Connect().Return(nil)
+	fsMock.EXPECT().DeleteFile("tjba/backups/tjba-2022-12.zip").Return(nil)
+	fsMock.EXPECT().DeleteFile("tjba/datapackage/tjba-2022-12.zip").Return(fsErr)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	report, err := client.DeleteMonthlyData("tjba", 12, 2022, true)
+
+	assert.ErrorIs(t, err, fsErr)
+	assert.Equal(t, d.report().Files[:1], report.RemovedFiles)
+}
+
+func (deleteMonthlyData) testWhenRepositoryReturnError(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	repoErr := errors.New("error deleting monthly data")
+	dbMock.EXPECT().DeleteMonthlyData("tjba", 12, 2022).Return(nil, repoErr)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	report, err := client.DeleteMonthlyData("tjba", 12, 2022, true)
+
+	assert.ErrorIs(t, err, repoErr)
+	assert.Nil(t, report)
+}
+
+func TestGetAnnualSummary(t *testing.T) {
+	tests := getAnnualSummary{}
+	t.Run("Test GetAnnualSummary when packages are in the catalog", tests.testWhenPackagesAreInCatalog)
+	t.Run("Test GetAnnualSummary when packages are not in the catalog", tests.testWhenPackagesAreNotInCatalog)
+}
+
+type getAnnualSummary struct{}
+
+func (getAnnualSummary) testWhenPackagesAreInCatalog(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	agency := "tjal"
+	year := 2022
+	scope := models.PackageScopeAnnual
+	pkg := models.Package{
+		Key:      "tjal/datapackage/tjal-2022.zip",
+		Scope:    scope,
+		AgencyID: &agency,
+		Year:     &year,
+		Package:  models.Backup{URL: "https://dadosjusbr-public.s3.amazonaws.com/tjal/datapackage/tjal-2022.zip", Hash: "abc", Size: 10},
+	}
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetAnnualSummary(agency).Return([]models.AnnualSummary{{Year: 2022}}, nil)
+	dbMock.EXPECT().GetPackages(models.PackageFilterOpts{AgencyID: &agency, Scope: &scope}).Return([]models.Package{pkg}, nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	summary, err := client.GetAnnualSummary(agency)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &pkg.Package, summary[0].Package)
+}
+
+func (getAnnualSummary) testWhenPackagesAreNotInCatalog(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	agency := "tjal"
+	scope := models.PackageScopeAnnual
+	bkp := &models.Backup{URL: "https://dadosjusbr-public.s3.amazonaws.com/tjal/datapackage/tjal-2021.zip", Hash: "def", Size: 20}
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetAnnualSummary(agency).Return([]models.AnnualSummary{{Year: 2021}}, nil)
+	dbMock.EXPECT().GetPackages(models.PackageFilterOpts{AgencyID: &agency, Scope: &scope}).Return(nil, nil)
+	fsMock.EXPECT().GetFile("tjal/datapackage/tjal-2021.zip").Return(bkp, nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	summary, err := client.GetAnnualSummary(agency)
+
+	assert.Nil(t, err)
+	assert.Equal(t, bkp, summary[0].Package)
+}
+
+func TestGetRanking(t *testing.T) {
+	tests := getRanking{}
+	t.Run("Test GetRanking when options are valid", tests.testWhenOptionsAreValid)
+	t.Run("Test GetRanking when options are invalid", tests.testWhenOptionsAreInvalid)
+	t.Run("Test GetRanking when repository returns error", tests.testWhenRepositoryReturnsError)
+}
+
+type getRanking struct{}
+
+func (getRanking) testWhenOptionsAreValid(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	month := 3
+	opts := models.RankingOpts{Metric: models.RankByBenefitsShare, Year: 2023, Month: &month}
+	entries := []models.RankingEntry{
+		{Position: 1, AgencyID: "tjba", Value: 0.4, Percentile: 100},
+		{Position: 2, AgencyID: "tjal", Value: 0.2, Percentile: 0},
+	}
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetRanking(opts).Return(entries, nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	ranking, err := client.GetRanking(opts)
+
+	assert.Nil(t, err)
+	assert.Equal(t, entries, ranking)
+}
+
+func (getRanking) testWhenOptionsAreInvalid(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	_, err = client.GetRanking(models.RankingOpts{Metric: "salario", Year: 2023})
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+	assert.ErrorContains(t, err, `invalid ranking metric: "salario"`)
+
+	month := 13
+	_, err = client.GetRanking(models.RankingOpts{Metric: models.RankByTransparencyIndex, Year: 2023, Month: &month})
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+	assert.ErrorContains(t, err, "invalid month: 13")
+}
+
+func (getRanking) testWhenRepositoryReturnsError(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	opts := models.RankingOpts{Metric: models.RankByRemunerationPerCapita, Year: 2023}
+	repoErr := models.NewError(models.ErrUnavailable, errors.New("connection refused"))
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetRanking(opts).Return(nil, repoErr)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	_, err = client.GetRanking(opts)
+
+	assert.ErrorIs(t, err, storage.ErrUnavailable)
+	assert.EqualError(t, err, "GetRanking() error: connection refused")
+}
+
+func TestGetItemTrends(t *testing.T) {
+	tests := getItemTrends{}
+	t.Run("Test GetItemTrends with deflation", tests.testWithDeflation)
+	t.Run("Test GetItemTrends when repository returns error", tests.testWhenRepositoryReturnsError)
+}
+
+type getItemTrends struct{}
+
+func (getItemTrends) testWithDeflation(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	opts := models.ItemRankingOpts{
+		Metric: models.RankItemsByTotal,
+		Period: models.Period{FromMonth: 1, FromYear: 2022, ToMonth: 2, ToYear: 2022},
+		Limit:  1,
+	}
+	aggOpts := models.AggregationOpts{DeflateTo: &models.MonthYear{Month: 1, Year: 2024}}
+	trends := []models.ItemTrend{{
+		Item:  "auxilio-alimentacao",
+		Total: 2000,
+		Months: []models.ItemMonthlyTotal{
+			{Month: 1, Year: 2022, Total: 1000},
+			{Month: 2, Year: 2022, Total: 1000},
+		},
+	}}
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetItemTrends(opts, aggOpts).Return(trends, nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	result, err := client.GetItemTrends(opts, aggOpts)
+
+	assert.Nil(t, err)
+	assert.Equal(t, trends, result)
+}
+
+func (getItemTrends) testWhenRepositoryReturnsError(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	opts := models.ItemRankingOpts{Metric: "media"}
+	repoErr := models.NewError(models.ErrInvalidInput, errors.New(`invalid item ranking metric: "media"`))
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetItemTrends(opts).Return(nil, repoErr)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	_, err = client.GetItemTrends(opts)
+
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+	assert.EqualError(t, err, `GetItemTrends() error: invalid item ranking metric: "media"`)
+}
+
+func TestGetOutlierPaychecks(t *testing.T) {
+	tests := getOutlierPaychecks{}
+	t.Run("Test GetOutlierPaychecks when options are valid", tests.testWhenOptionsAreValid)
+	t.Run("Test GetOutlierPaychecks when options are invalid", tests.testWhenOptionsAreInvalid)
+	t.Run("Test GetOutlierPaychecks when repository returns error", tests.testWhenRepositoryReturnsError)
+}
+
+type getOutlierPaychecks struct{}
+
+func (getOutlierPaychecks) testWhenOptionsAreValid(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	month := 5
+	opts := models.OutlierOpts{AgencyID: "tjsp", Year: 2022, Month: &month, Items: []string{"gratificacao"}}
+	outliers := []models.OutlierPaycheck{{
+		PaycheckID: 1,
+		AgencyID:   "tjsp",
+		Month:      5,
+		Year:       2022,
+		Name:       "maria",
+		Role:       "JUIZ DE DIREITO",
+		Reason:     models.OutlierItem,
+		Item:       "gratificacao",
+		Value:      30000,
+		Score:      26.98,
+		Peers:      models.PeerStats{NumPeers: 6, Median: 10000, MAD: 500, MeanAbsDeviation: 600, Scale: 741.3},
+	}}
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetOutlierPaychecks(opts).Return(outliers, nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	result, err := client.GetOutlierPaychecks(opts)
+
+	assert.Nil(t, err)
+	assert.Equal(t, outliers, result)
+}
+
+func (getOutlierPaychecks) testWhenOptionsAreInvalid(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	_, err = client.GetOutlierPaychecks(models.OutlierOpts{Year: 2022})
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+	assert.ErrorContains(t, err, "agency cannot be empty")
+
+	_, err = client.GetOutlierPaychecks(models.OutlierOpts{AgencyID: "tjsp"})
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+	assert.ErrorContains(t, err, "invalid year: 0")
+
+	month := 0
+	_, err = client.GetOutlierPaychecks(models.OutlierOpts{AgencyID: "tjsp", Year: 2022, Month: &month})
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+	assert.ErrorContains(t, err, "invalid month: 0")
+}
+
+func (getOutlierPaychecks) testWhenRepositoryReturnsError(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	opts := models.OutlierOpts{AgencyID: "tjsp", Year: 2022}
+	repoErr := models.NewError(models.ErrUnavailable, errors.New("connection refused"))
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetOutlierPaychecks(opts).Return(nil, repoErr)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	_, err = client.GetOutlierPaychecks(opts)
+
+	assert.ErrorIs(t, err, storage.ErrUnavailable)
+	assert.EqualError(t, err, "GetOutlierPaychecks() error: connection refused")
+}
+
+func TestGetCoverage(t *testing.T) {
+	tests := getCoverage{}
+	t.Run("Test GetCoverage until the current month", tests.testUntilCurrentMonth)
+	t.Run("Test GetCoverage when repository returns error", tests.testWhenRepositoryReturnsError)
+}
+
+type getCoverage struct{}
+
+func (getCoverage) testUntilCurrentMonth(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	agencies := []models.Agency{{ID: "tjba"}}
+	coverage := []models.AgencyCoverage{{
+		AgencyID:   "tjba",
+		NumMissing: 1,
+		Cells: []models.CoverageCell{
+			{Month: 2, Year: 2023, HasCollection: true},
+			{Month: 3, Year: 2023, Missing: true},
+		},
+	}}
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetCoverage(agencies, models.MonthYear{Month: 3, Year: 2023}).Return(coverage, nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	client.Clock = func() time.Time { return time.Date(2023, 3, 15, 10, 0, 0, 0, time.UTC) }
+	result, err := client.GetCoverage(agencies)
+
+	assert.Nil(t, err)
+	assert.Equal(t, coverage, result)
+}
+
+func (getCoverage) testWhenRepositoryReturnsError(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	repoErr := models.NewError(models.ErrUnavailable, errors.New("connection refused"))
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetCoverage(nil, models.MonthYear{Month: 12, Year: 2022}).Return(nil, repoErr)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	client.Clock = func() time.Time { return time.Date(2022, 12, 31, 23, 59, 0, 0, time.UTC) }
+	_, err = client.GetCoverage(nil)
+
+	assert.ErrorIs(t, err, storage.ErrUnavailable)
+	assert.EqualError(t, err, "GetCoverage() error: connection refused")
+}
+
+func TestUploadPackage(t *testing.T) {
+	tests := uploadPackage{}
+	t.Run("Test UploadPackage when file is uploaded", tests.testWhenFileIsUploaded)
+	t.Run("Test UploadPackage when file storage return error", tests.testWhenFileStorageReturnError)
+}
+
+type uploadPackage struct{}
+
+func (uploadPackage) testWhenFileIsUploaded(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	bkp := &models.Backup{URL: "https://dadosjusbr-public.s3.amazonaws.com/dumps/dadosjusbr-2023-5.zip", Hash: "abc", Size: 10}
+	expected := models.Package{Key: "dumps/dadosjusbr-2023-5.zip", Scope: models.PackageScopeDump, Package: *bkp}
+	dbMock.EXPECT().Connect().Return(nil)
+	fsMock.EXPECT().UploadFile("dadosjusbr-2023-5.zip", "dumps/dadosjusbr-2023-5.zip").Return(bkp, nil)
+	dbMock.EXPECT().StorePackage(expected).Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	pkg, err := client.UploadPackage("dadosjusbr-2023-5.zip", models.Package{Key: "dumps/dadosjusbr-2023-5.zip"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, &expected, pkg)
+}
+
+func (uploadPackage) testWhenFileStorageReturnError(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	fsErr := errors.New("error uploading file")
+	dbMock.EXPECT().Connect().Return(nil)
+	fsMock.EXPECT().UploadFile("dadosjusbr-2023-5.zip", "dumps/dadosjusbr-2023-5.zip").Return(nil, fsErr)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	pkg, err := client.UploadPackage("dadosjusbr-2023-5.zip", models.Package{Key: "dumps/dadosjusbr-2023-5.zip"})
+
+	assert.ErrorIs(t, err, fsErr)
+	assert.Nil(t, pkg)
+}
+
+func TestStoreCollection(t *testing.T) {
+	tests := storeCollection{}
+	t.Run("Test StoreCollection when repository store data", tests.testWhenRepositoryStoreData)
+	t.Run("Test StoreCollection when repository return error", tests.testWhenRepositoryReturnError)
+}
+
+type storeCollection struct{}
+
+func (storeCollection) testWhenRepositoryStoreData(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	col := models.Collection{
+		MonthlyInfo: models.AgencyMonthlyInfo{AgencyID: "tjsp", Month: 1, Year: 2020},
+		Paychecks:   []models.Paycheck{{ID: 1, Agency: "tjsp", Month: 1, Year: 2020}},
+	}
+	dbMock.EXPECT().GetAgency("tjsp").Return(&models.Agency{ID: "tjsp"}, nil)
+	dbMock.EXPECT().StoreCollection(col).Return(nil)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.StoreCollection(col)
+
+	assert.Nil(t, err)
+}
+
+func (storeCollection) testWhenRepositoryReturnError(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	col := models.Collection{MonthlyInfo: models.AgencyMonthlyInfo{AgencyID: "tjsp", Month: 1, Year: 2020}}
+	repoErr := errors.New("error storing collection")
+	dbMock.EXPECT().GetAgency("tjsp").Return(&models.Agency{ID: "tjsp"}, nil)
+	dbMock.EXPECT().StoreCollection(col).Return(repoErr)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.StoreCollection(col)
+
+	assert.ErrorIs(t, err, repoErr)
+}
+
+func TestReplacePaychecks(t *testing.T) {
+	tests := replacePaychecks{}
+	t.Run("Test ReplacePaychecks when repository replace paychecks", tests.testWhenRepositoryReplacePaychecks)
+	t.Run("Test ReplacePaychecks when repository return error", tests.testWhenRepositoryReturnError)
+	t.Run("Test ReplacePaychecks when the batch spans several months", tests.testWhenBatchSpansSeveralMonths)
+}
+
+type replacePaychecks struct{}
+
+func (replacePaychecks) testWhenRepositoryReplacePaychecks(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	p := []models.Paycheck{{ID: 1, Agency: "tjsp", Month: 1, Year: 2020}}
+	report := &models.PaycheckStoreReport{PaychecksUpdated: 1, PaychecksDeleted: 3}
+	dbMock.EXPECT().GetAgency("tjsp").Return(&models.Agency{ID: "tjsp"}, nil)
+	dbMock.EXPECT().ReplacePaychecks(p, nil).Return(report, nil)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	returnedReport, err := client.ReplacePaychecks(p, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, report, returnedReport)
+}
+
+func (replacePaychecks) testWhenRepositoryReturnError(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	repoErr := errors.New("error replacing paychecks")
+	dbMock.EXPECT().ReplacePaychecks(nil, nil).Return(nil, repoErr)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	report, err := client.ReplacePaychecks(nil, nil)
+
+	assert.ErrorIs(t, err, repoErr)
+	assert.Nil(t, report)
+}
+
+func (replacePaychecks) testWhenBatchSpansSeveralMonths(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	p := []models.Paycheck{
+		{ID: 1, Agency: "tjsp", Month: 1, Year: 2020},
+		{ID: 2, Agency: "tjsp", Month: 2, Year: 2020},
+	}
+	dbMock.EXPECT().GetAgency("tjsp").Return(&models.Agency{ID: "tjsp"}, nil)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	report, err := client.ReplacePaychecks(p, nil)
+
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+	var validationErr *models.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []models.FieldError{
+		{Field: "paychecks[1]", Message: "belongs to tjsp/02/2020, but the write is for tjsp/01/2020"},
+	}, validationErr.Errors)
+	assert.Nil(t, report)
+}
+
+func TestBulkStorePaychecks(t *testing.T) {
+	tests := bulkStorePaychecks{}
+	t.Run("Test BulkStorePaychecks when repository store paychecks", tests.testWhenRepositoryStorePaychecks)
+	t.Run("Test BulkStorePaychecks when repository return error", tests.testWhenRepositoryReturnError)
+}
+
+type bulkStorePaychecks struct{}
+
+func (bulkStorePaychecks) testWhenRepositoryStorePaychecks(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	p := []models.Paycheck{{ID: 1, Agency: "tjsp", Month: 1, Year: 2020}}
+	pi := []models.PaycheckItem{{ID: 1, PaycheckID: 1, Agency: "tjsp", Month: 1, Year: 2020}}
+	dbMock.EXPECT().GetAgency("tjsp").Return(&models.Agency{ID: "tjsp"}, nil)
+	dbMock.EXPECT().BulkStorePaychecks(p, pi, nil).Return(nil)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.BulkStorePaychecks(p, pi, nil)
+
+	assert.Nil(t, err)
+}
+
+func (bulkStorePaychecks) testWhenRepositoryReturnError(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	repoErr := errors.New("error copying paychecks")
+	dbMock.EXPECT().BulkStorePaychecks(nil, nil, nil).Return(repoErr)
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.BulkStorePaychecks(nil, nil, nil)
+
+	assert.ErrorIs(t, err, repoErr)
+}
+
+func TestHealth(t *testing.T) {
+	tests := health{}
+	t.Run("Test Health when all components are OK", tests.testWhenAllComponentsAreOK)
+	t.Run("Test Health when components fail", tests.testWhenComponentsFail)
+}
+
+type health struct{}
+
+func (health) testWhenAllComponentsAreOK(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	ctx := context.Background()
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().Ping(ctx).Return(nil)
+	dbMock.EXPECT().GetSchemaVersion(ctx).Return(database.SchemaVersion, nil)
+	dbMock.EXPECT().GetMissingViews(ctx).Return(nil, nil)
+	fsMock.EXPECT().Ping(ctx).Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	h, err := client.Health(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.HealthStatusOK, h.Status)
+	assert.Len(t, h.Components, 4)
+	for _, c := range h.Components {
+		assert.Equal(t, models.HealthStatusOK, c.Status)
+		assert.Empty(t, c.Error)
+	}
+}
+
+func (health) testWhenComponentsFail(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	ctx := context.Background()
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().Ping(ctx).Return(nil)
+	dbMock.EXPECT().GetSchemaVersion(ctx).Return(database.SchemaVersion-1, nil)
+	dbMock.EXPECT().GetMissingViews(ctx).Return([]string{"media_por_membro"}, nil)
+	fsMock.EXPECT().Ping(ctx).Return(errors.New("bucket unreachable"))
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	h, err := client.Health(ctx)
+
+	assert.ErrorIs(t, err, storage.ErrUnavailable)
+	assert.Equal(t, models.HealthStatusFailed, h.Status)
+	statuses := make(map[string]models.ComponentHealth)
+	for _, c := range h.Components {
+		statuses[c.Name] = c
+	}
+	assert.Equal(t, models.HealthStatusOK, statuses[models.HealthComponentDatabase].Status)
+	assert.Equal(t, models.HealthStatusFailed, statuses[models.HealthComponentSchema].Status)
+	assert.Equal(t, "missing materialized views: media_por_membro", statuses[models.HealthComponentMaterializedViews].Error)
+	assert.Equal(t, "bucket unreachable", statuses[models.HealthComponentFileStorage].Error)
+}
+
+func TestVerifySummary(t *testing.T) {
+	tests := verifySummary{}
+	t.Run("Test VerifySummary when summaries match", tests.testWhenSummariesMatch)
+	t.Run("Test VerifySummary when summaries differ", tests.testWhenSummariesDiffer)
+	t.Run("Test VerifySummary when there are no paychecks", tests.testWhenThereAreNoPaychecks)
+}
+
+type verifySummary struct{}
+
+func (verifySummary) testWhenSummariesMatch(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	summary := &models.Summary{
+		Count:            2,
+		BaseRemuneration: models.DataSummary{Max: 60000, Min: 9000, Average: 34500, Total: 69000},
+		IncomeHistogram:  map[int]int{10000: 1, -1: 1},
+		ItemSummary:      models.ItemSummary{"ferias": 3000},
+	}
+	// O crawler não envia as faixas vazias do histograma.
+	computed := *summary
+	computed.IncomeHistogram = map[int]int{10000: 1, 20000: 0, 30000: 0, 40000: 0, 50000: 0, -1: 1}
+	computed.BaseRemuneration.Average = 34500.004
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetOMA(1, 2023, "tjba").Return(&models.AgencyMonthlyInfo{AgencyID: "tjba", Month: 1, Year: 2023, Summary: summary}, &models.Agency{ID: "tjba"}, nil)
+	dbMock.EXPECT().ComputeSummary("tjba", 1, 2023).Return(&computed, nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	v, err := client.VerifySummary("tjba", 1, 2023)
+
+	assert.Nil(t, err)
+	assert.True(t, v.OK())
+	assert.Empty(t, v.Discrepancies)
+}
+
+func (verifySummary) testWhenSummariesDiffer(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	stored := &models.Summary{
+		Count:            3,
+		BaseRemuneration: models.DataSummary{Max: 60000, Min: 9000, Average: 34500, Total: 69000},
+		ItemSummary:      models.ItemSummary{"ferias": 3000, "outras": 500},
+	}
+	computed := &models.Summary{
+		Count:            2,
+		BaseRemuneration: models.DataSummary{Max: 60000, Min: 9000, Average: 34500, Total: 69000},
+		IncomeHistogram:  map[int]int{10000: 1},
+		ItemSummary:      models.ItemSummary{"ferias": 3000, "outras": 1000},
+	}
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetOMA(1, 2023, "tjba").Return(&models.AgencyMonthlyInfo{AgencyID: "tjba", Month: 1, Year: 2023, Summary: stored}, &models.Agency{ID: "tjba"}, nil)
+	dbMock.EXPECT().ComputeSummary("tjba", 1, 2023).Return(computed, nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	v, err := client.VerifySummary("tjba", 1, 2023)
+
+	assert.Nil(t, err)
+	assert.False(t, v.OK())
+	assert.Equal(t, []models.SummaryDiscrepancy{
+		{Field: "membros", Stored: 3, Computed: 2},
+		{Field: "histograma_renda.10000", Stored: 0, Computed: 1},
+		{Field: "resumo_rubricas.outras", Stored: 500, Computed: 1000},
+	}, v.Discrepancies)
+}
+
+func (verifySummary) testWhenThereAreNoPaychecks(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	repoErr := models.NewError(models.ErrNotFound, errors.New("there are no paychecks for tjba/01/2023"))
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetOMA(1, 2023, "tjba").Return(&models.AgencyMonthlyInfo{AgencyID: "tjba"}, &models.Agency{ID: "tjba"}, nil)
+	dbMock.EXPECT().ComputeSummary("tjba", 1, 2023).Return(nil, repoErr)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	v, err := client.VerifySummary("tjba", 1, 2023)
+
+	assert.Nil(t, v)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	tests := detectAnomalies{}
+	t.Run("Test DetectAnomalies when other remunerations triple", tests.testWhenOtherRemunerationsTriple)
+	t.Run("Test DetectAnomalies when the window is too short", tests.testWhenWindowIsTooShort)
+	t.Run("Test DetectAnomalies when rubricas appear and disappear", tests.testWhenRubricasAppearAndDisappear)
+	t.Run("Test StoreAnomalies when anomalies are invalid", tests.testStoreWhenAnomaliesAreInvalid)
+}
+
+type detectAnomalies struct{}
+
+// monthlyInfos retorna as coletas de 2022 do tjba, com pequenas variações de um mês para o outro,
+// e a coleta de 01/2023, em que as outras remunerações triplicam e surge uma rubrica nova.
+func (detectAnomalies) monthlyInfos() []models.AgencyMonthlyInfo {
+	var agmis []models.AgencyMonthlyInfo
+	for month := 1; month <= 12; month++ {
+		agmis = append(agmis, models.AgencyMonthlyInfo{AgencyID: "tjba", Month: month, Year: 2022, Summary: &models.Summary{
+			Count:              100,
+			BaseRemuneration:   models.DataSummary{Total: 50000 + float64(month%2)*500},
+			OtherRemunerations: models.DataSummary{Total: 10000 + float64(month%3)*100},
+			ItemSummary:        models.ItemSummary{"ferias": 1000},
+		}})
+	}
+	return append(agmis, models.AgencyMonthlyInfo{AgencyID: "tjba", Month: 1, Year: 2023, Summary: &models.Summary{
+		Count:              100,
+		BaseRemuneration:   models.DataSummary{Total: 50500},
+		OtherRemunerations: models.DataSummary{Total: 30300},
+		ItemSummary:        models.ItemSummary{"ferias": 1000, "gratificacao": 19300},
+	}})
+}
+
+func (d detectAnomalies) testWhenOtherRemunerationsTriple(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetMonthlyInfoInPeriod([]models.Agency{{ID: "tjba"}}, models.Period{FromMonth: 1, FromYear: 2022, ToMonth: 1, ToYear: 2023}).
+		Return(map[string][]models.AgencyMonthlyInfo{"tjba": d.monthlyInfos()}, nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	anomalies, err := client.DetectAnomalies("tjba", models.Period{FromMonth: 1, FromYear: 2023, ToMonth: 1, ToYear: 2023})
+
+	assert.Nil(t, err)
+	assert.Len(t, anomalies, 1)
+	assert.Equal(t, models.AnomalyOtherRemunerations, anomalies[0].Metric)
+	assert.Equal(t, 30300.0, anomalies[0].Value)
+	assert.Equal(t, 10100.0, anomalies[0].Median)
+	assert.InDelta(t, 20200/148.26, anomalies[0].Score, 0.001)
+}
+
+func (d detectAnomalies) testWhenWindowIsTooShort(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	// Apenas 3 meses na janela, menos que o mínimo.
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetMonthlyInfoInPeriod([]models.Agency{{ID: "tjba"}}, models.Period{FromMonth: 10, FromYear: 2022, ToMonth: 1, ToYear: 2023}).
+		Return(map[string][]models.AgencyMonthlyInfo{"tjba": d.monthlyInfos()[9:]}, nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	anomalies, err := client.DetectAnomalies("tjba", models.Period{FromMonth: 1, FromYear: 2023, ToMonth: 1, ToYear: 2023}, models.AnomalyOpts{Window: 3})
+
+	assert.Nil(t, err)
+	assert.Empty(t, anomalies)
+}
+
+func (d detectAnomalies) testWhenRubricasAppearAndDisappear(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	// Em 01/2023, as férias (constantes em 2022) deixam de ser pagas e a gratificação, que
+	// nunca foi paga, surge. Janelas constantes usam 5% da mediana como escala; janelas
+	// zeradas não têm escala e não são analisadas.
+	agmis := d.monthlyInfos()
+	agmis[12].Summary.ItemSummary = models.ItemSummary{"gratificacao": 19300}
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetMonthlyInfoInPeriod([]models.Agency{{ID: "tjba"}}, models.Period{FromMonth: 1, FromYear: 2022, ToMonth: 1, ToYear: 2023}).
+		Return(map[string][]models.AgencyMonthlyInfo{"tjba": agmis}, nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	anomalies, err := client.DetectAnomalies("tjba", models.Period{FromMonth: 1, FromYear: 2023, ToMonth: 1, ToYear: 2023})
+
+	assert.Nil(t, err)
+	assert.Len(t, anomalies, 2)
+	assert.Equal(t, models.AnomalyItem, anomalies[1].Metric)
+	assert.Equal(t, "ferias", anomalies[1].Item)
+	assert.Equal(t, 0.0, anomalies[1].Value)
+	assert.InDelta(t, -1000/50.0, anomalies[1].Score, 0.001)
+}
+
+func (detectAnomalies) testStoreWhenAnomaliesAreInvalid(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().GetAgency("tjba").Return(&models.Agency{ID: "tjba"}, nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.StoreAnomalies("tjba", models.Period{FromMonth: 1, FromYear: 2023, ToMonth: 1, ToYear: 2023}, []models.Anomaly{
+		{AgencyID: "tjba", Month: 1, Year: 2023, Metric: models.AnomalyMembers},
+		{AgencyID: "tjsp", Month: 2, Year: 2023, Metric: models.AnomalyMembers},
+	})
+
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+	var validationErr *models.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []models.FieldError{
+		{Field: "anomalies[1].aid", Message: "must be tjba, got tjsp"},
+		{Field: "anomalies[1]", Message: "02/2023 is not in the period 01/2023-01/2023"},
+	}, validationErr.Errors)
+}
+
+func TestStoreRemunerationCaps(t *testing.T) {
+	tests := storeRemunerationCaps{}
+	t.Run("Test StoreRemunerationCaps when caps are valid", tests.testWhenCapsAreValid)
+	t.Run("Test StoreRemunerationCaps when caps are invalid", tests.testWhenCapsAreInvalid)
+}
+
+type storeRemunerationCaps struct{}
+
+func (storeRemunerationCaps) testWhenCapsAreValid(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	caps := []models.RemunerationCap{
+		{Month: 1, Year: 2019, Value: 39293.32},
+		{Month: 4, Year: 2023, Value: 41650.92},
+	}
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().StoreRemunerationCaps(caps).Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.StoreRemunerationCaps(caps)
+
+	assert.Nil(t, err)
+}
+
+func (storeRemunerationCaps) testWhenCapsAreInvalid(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	caps := []models.RemunerationCap{
+		{Month: 13, Year: 2019, Value: 39293.32},
+		{Month: 4, Year: 2023, Value: 0},
+		{Month: 4, Year: 2023, Value: 41650.92},
+	}
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.StoreRemunerationCaps(caps)
+
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+	var validationErr *models.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []models.FieldError{
+		{Field: "caps[0].month", Message: "must be between 1 and 12, got 13"},
+		{Field: "caps[1].value", Message: "must be positive, got 0"},
+		{Field: "caps[2]", Message: "duplicated cap for 04/2023"},
+	}, validationErr.Errors)
+}
+
+func TestStoreRoleNormalizations(t *testing.T) {
+	tests := storeRoleNormalizations{}
+	t.Run("Test StoreRoleNormalizations when rules are valid", tests.testWhenRulesAreValid)
+	t.Run("Test StoreRoleNormalizations when rules are invalid", tests.testWhenRulesAreInvalid)
+}
+
+type storeRoleNormalizations struct{}
+
+func (storeRoleNormalizations) testWhenRulesAreValid(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	rules := []models.RoleNormalization{
+		{Pattern: "juiz de direito%", Role: "Juiz de Direito"},
+		{Pattern: "juiz substituto%", Role: "Juiz Substituto"},
+	}
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().StoreRoleNormalizations(rules).Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.StoreRoleNormalizations(rules)
+
+	assert.Nil(t, err)
+}
+
+func (storeRoleNormalizations) testWhenRulesAreInvalid(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	rules := []models.RoleNormalization{
+		{Pattern: " ", Role: "Juiz de Direito"},
+		{Pattern: "juiz de direito%", Role: ""},
+		{Pattern: "JUIZ DE DIREITO%", Role: "Juiz de Direito"},
+	}
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.StoreRoleNormalizations(rules)
+
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+	var validationErr *models.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []models.FieldError{
+		{Field: "roles[0].pattern", Message: "cannot be empty"},
+		{Field: "roles[1].role", Message: "cannot be empty"},
+		{Field: "roles[2]", Message: `duplicated pattern "JUIZ DE DIREITO%"`},
+	}, validationErr.Errors)
+}
+
+func TestLoadPriceIndex(t *testing.T) {
+	tests := loadPriceIndex{}
+	t.Run("Test LoadPriceIndex with comma separated file", tests.testWithCommaSeparatedFile)
+	t.Run("Test LoadPriceIndex with semicolon separated file", tests.testWithSemicolonSeparatedFile)
+	t.Run("Test LoadPriceIndex with thousands separators", tests.testWithThousandsSeparators)
+	t.Run("Test LoadPriceIndex with invalid file", tests.testWithInvalidFile)
+	t.Run("Test LoadPriceIndex with invalid values", tests.testWithInvalidValues)
+}
+
+type loadPriceIndex struct{}
+
+func writePriceIndexFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "ipca.csv")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("error writing price index file: %q", err)
+	}
+	return path
+}
+
+func (loadPriceIndex) testWithCommaSeparatedFile(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().StorePriceIndex([]models.PriceIndex{
+		{Year: 2023, Month: 1, Value: 6474.09},
+		{Year: 2023, Month: 2, Value: 6524.32},
+	}).Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.LoadPriceIndex(writePriceIndexFile(t, "2023,1,6474.09\n2023,2,6524.32\n"))
+
+	assert.Nil(t, err)
+}
+
+func (loadPriceIndex) testWithSemicolonSeparatedFile(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().StorePriceIndex([]models.PriceIndex{
+		{Year: 2023, Month: 1, Value: 6474.09},
+		{Year: 2023, Month: 2, Value: 6524.32},
+	}).Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.LoadPriceIndex(writePriceIndexFile(t, "ano;mes;valor\n2023;1;6474,09\n2023;2;6524,32\n"))
+
+	assert.Nil(t, err)
+}
+
+func (loadPriceIndex) testWithThousandsSeparators(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	dbMock.EXPECT().Connect().Return(nil)
+	dbMock.EXPECT().StorePriceIndex([]models.PriceIndex{
+		{Year: 2023, Month: 1, Value: 6474.09},
+		{Year: 2023, Month: 2, Value: 6524.32},
+		{Year: 2023, Month: 3, Value: 1234567.8},
+	}).Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.LoadPriceIndex(writePriceIndexFile(t, "ano;mes;valor\n2023;1;6.474,09\n2023;2;6524,32\n2023;3;1.234.567,8\n"))
+
+	assert.Nil(t, err)
+}
+
+func (loadPriceIndex) testWithInvalidFile(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.LoadPriceIndex(writePriceIndexFile(t, "ano,mes,valor\n2023,1,6474.09\n2023,fev,6524.32\n"))
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+	assert.ErrorContains(t, err, "line 3")
+
+	err = client.LoadPriceIndex(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.ErrorIs(t, err, storage.ErrInvalidInput)
+}
+
+func (loadPriceIndex) testWithInvalidValues(t *testing.T) {
+	mockCrl := gomock.NewController(t)
+	dbMock := database.NewMockInterface(mockCrl)
+	fsMock := file_storage.NewMockInterface(mockCrl)
+
+	dbMock.EXPECT().Connect().Return(nil)
+
+	client, err := storage.NewClient(dbMock, fsMock)
+	err = client.LoadPriceIndex(writePriceIndexFile(t, "2023,0,6474.09\n2023,2,-1\n2023,2,6524.32\n"))
+
+	var validationErr *models.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []models.FieldError{
+		{Field: "price_index[0].month", Message: "must be between 1 and 12, got 0"},
+		{Field: "price_index[1].value", Message: "must be positive, got -1"},
+		{Field: "price_index[2]", Message: "duplicated value for 02/2023"},
+	}, validationErr.Errors)
+}
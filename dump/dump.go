@@ -7,6 +7,7 @@ import (
 
 	"github.com/dadosjusbr/datapackage"
 	"github.com/dadosjusbr/storage"
+	"github.com/dadosjusbr/storage/models"
 	"github.com/dadosjusbr/storage/repo/database"
 	"github.com/dadosjusbr/storage/repo/file_storage"
 	dpkg "github.com/frictionlessdata/datapackage-go/datapackage"
@@ -68,8 +69,8 @@ func main() {
 		log.Fatalf("error loading datapackage: %v", err)
 	}
 
-	// Armazenando no S3
-	_, err = pgS3Client.Cloud.UploadFile(pkgName, "dumps/"+pkgName)
+	// Armazenando no S3 e registrando no catálogo de pacotes
+	_, err = pgS3Client.UploadPackage(pkgName, models.Package{Key: "dumps/" + pkgName, Scope: models.PackageScopeDump})
 	if err != nil {
 		log.Fatalf("error while uploading dump (%s): %v", pkgName, err)
 	}
@@ -0,0 +1,12 @@
+package storage
+
+import "github.com/dadosjusbr/storage/models"
+
+// Errors returned by the Client. Use errors.Is to check them and errors.As with
+// *models.Error to get the classified error.
+var (
+	ErrNotFound     = models.ErrNotFound
+	ErrConflict     = models.ErrConflict
+	ErrInvalidInput = models.ErrInvalidInput
+	ErrUnavailable  = models.ErrUnavailable
+)
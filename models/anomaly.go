@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// AnomalyMetric is a monthly series of an agency checked for anomalies.
+type AnomalyMetric string
+
+const (
+	AnomalyMembers            AnomalyMetric = "membros"
+	AnomalyBaseRemuneration   AnomalyMetric = "remuneracao_base"    // Total
+	AnomalyOtherRemunerations AnomalyMetric = "outras_remuneracoes" // Total
+	AnomalyItem               AnomalyMetric = "rubrica"             // Total of a rubrica of the item summary
+)
+
+// AnomalyOpts are the parameters of the anomaly detection. Zero fields take the default values.
+type AnomalyOpts struct {
+	Window          int     `json:"window,omitempty"`           // Number of previous months compared with each month (12)
+	MinObservations int     `json:"min_observations,omitempty"` // Minimum number of collected months in the window (6)
+	Threshold       float64 `json:"threshold,omitempty"`        // Minimum absolute robust z-score of an anomaly (3.5)
+}
+
+// Anomaly is a month whose value of a metric is unusual when compared with the previous months.
+// The scale of the score is computed over the window as described in PeerStats. Windows with
+// only zeros (e.g. a rubrica paid for the first time) have no scale and are skipped.
+type Anomaly struct {
+	AgencyID   string        `json:"aid"`
+	Month      int           `json:"month"`
+	Year       int           `json:"year"`
+	Metric     AnomalyMetric `json:"metric"`
+	Item       string        `json:"item,omitempty"` // Rubrica, when the metric is AnomalyItem
+	Value      float64       `json:"value"`
+	Median     float64       `json:"median"` // Median of the window
+	Score      float64       `json:"score"`  // Robust z-score: (value - median) / scale (see above)
+	DetectedAt time.Time     `json:"detected_at,omitempty"`
+}
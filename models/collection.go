@@ -0,0 +1,11 @@
+package models
+
+// Collection A Struct containing everything produced by the collection of an agency in a month.
+// It is stored all-or-nothing by StoreCollection.
+type Collection struct {
+	MonthlyInfo         AgencyMonthlyInfo     `json:"monthly_info"`
+	Paychecks           []Paycheck            `json:"paychecks,omitempty"`
+	PaycheckItems       []PaycheckItem        `json:"paycheck_items,omitempty"`
+	RetroactivePayments []RetroactivePayments `json:"retroactive_payments,omitempty"`
+	Remunerations       *Remunerations        `json:"remunerations,omitempty"` // Metadata of the remunerations zip. Optional.
+}
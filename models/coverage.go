@@ -0,0 +1,29 @@
+package models
+
+// CoverageCell is the data available for an agency in a month. A month without a current
+// collection without errors is NotCollecting when the collecting status of the agency in the
+// month (see AgencyCoverage) is Collecting: false, i.e. the agency is known not to publish the
+// data, and Missing otherwise.
+type CoverageCell struct {
+	Month                int      `json:"month"`
+	Year                 int      `json:"year"`
+	HasCollection        bool     `json:"has_collection"`       // There is a current collection without errors
+	HasPaychecks         bool     `json:"has_paychecks"`        // Paychecks are loaded in 'contracheques'
+	HasRemunerationZip   bool     `json:"has_remuneration_zip"` // There is a remuneration zip ('remuneracoes_zips')
+	Inconsistent         bool     `json:"inconsistent"`         // Some remuneration of the month is marked as inconsistent
+	NotCollecting        bool     `json:"not_collecting"`
+	NotCollectingReasons []string `json:"not_collecting_reasons,omitempty"` // Description of the status
+	Missing              bool     `json:"missing"`
+}
+
+// AgencyCoverage is the data coverage of an agency, with one cell per month from the first
+// monitored month (the first month with a collection, successful or not) or the month of the
+// first collecting status with timestamp, whichever comes first, on. The status of a month is
+// the latest status whose timestamp is in the month or before it; statuses without timestamp
+// are not used in the cells.
+type AgencyCoverage struct {
+	AgencyID   string         `json:"aid"`
+	Collecting *Collecting    `json:"collecting,omitempty"` // Latest collecting status of the agency
+	NumMissing int            `json:"num_missing"`          // Cells with Missing set
+	Cells      []CoverageCell `json:"cells,omitempty"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// DeletionReport A Struct containing what was removed when deleting an agency's data for a month.
+type DeletionReport struct {
+	AgencyID            string   `json:"aid,omitempty"`
+	Month               int      `json:"month,omitempty"`
+	Year                int      `json:"year,omitempty"`
+	Collections         int64    `json:"collections"`             // Number of revisions removed from 'coletas'
+	Paychecks           int64    `json:"paychecks"`               // Number of rows removed from 'contracheques'
+	PaycheckItems       int64    `json:"paycheck_items"`          // Number of rows removed from 'remuneracoes'
+	RetroactivePayments int64    `json:"retroactive_payments"`    // Number of rows removed from 'retroativos'
+	RemunerationZips    int64    `json:"remuneration_zips"`       // Number of rows removed from 'remuneracoes_zips'
+	Packages            int64    `json:"packages"`                // Number of monthly packages removed from 'pacotes'
+	Anomalies           int64    `json:"anomalies"`               // Number of rows removed from 'anomalias'
+	Files               []string `json:"files,omitempty"`         // URLs of the files (backups, packages and zips) related to the removed rows
+	RemovedFiles        []string `json:"removed_files,omitempty"` // URLs of the files removed from the file storage
+}
@@ -0,0 +1,32 @@
+package models
+
+import "errors"
+
+// Sentinel errors returned by the storage library. They can be checked with errors.Is,
+// e.g. errors.Is(err, models.ErrNotFound), and mapped to HTTP status codes.
+var (
+	ErrNotFound     = errors.New("not found")     // The requested data does not exist
+	ErrConflict     = errors.New("conflict")      // The data conflicts with data already stored
+	ErrInvalidInput = errors.New("invalid input") // The given data or parameters are invalid
+	ErrUnavailable  = errors.New("unavailable")   // The database or file storage could not be reached
+)
+
+// Error is an error classified by the storage library. Kind is one of the sentinel errors
+// above and Err is the underlying error. Both can be inspected with errors.Is and errors.As.
+type Error struct {
+	Kind error // ErrNotFound, ErrConflict, ErrInvalidInput or ErrUnavailable
+	Err  error // Underlying error
+}
+
+// NewError classifies err with the given kind.
+func NewError(kind, err error) error {
+	return &Error{Kind: kind, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
@@ -0,0 +1,25 @@
+package models
+
+// AgencyGrouping is an attribute of the agencies used to group the aggregations.
+type AgencyGrouping string
+
+const (
+	GroupByJurisdiction AgencyGrouping = "jurisdicao" // e.g. Estadual, Federal, Trabalho
+	GroupByUF           AgencyGrouping = "uf"
+	GroupByRegion       AgencyGrouping = "regiao"   // Macro-region derived from the UF, see Regions
+	GroupByEntity       AgencyGrouping = "entidade" // e.g. Tribunal, Ministério
+)
+
+// GroupedMonthlyInfo contains the totals of a month for a group of agencies.
+type GroupedMonthlyInfo struct {
+	Group              string      `json:"group"`
+	Year               int         `json:"year,omitempty"`
+	Month              int         `json:"month,omitempty"`
+	NumAgencies        int         `json:"num_agencies,omitempty"` // Number of agencies with data in the month
+	Count              int         `json:"count,omitempty"`        // Number of employees
+	BaseRemuneration   float64     `json:"base_remuneration,omitempty"`
+	OtherRemunerations float64     `json:"other_remunerations,omitempty"`
+	Discounts          float64     `json:"discounts,omitempty"`
+	Remunerations      float64     `json:"remunerations,omitempty"`
+	ItemSummary        ItemSummary `json:"item_summary,omitempty"`
+}
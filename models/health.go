@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// HealthStatus is the status of a component checked by the health check.
+type HealthStatus string
+
+const (
+	HealthStatusOK     HealthStatus = "ok"     // The component is usable
+	HealthStatusFailed HealthStatus = "failed" // The component is not usable
+)
+
+// Names of the components checked by the health check.
+const (
+	HealthComponentDatabase          = "database"           // Database connection
+	HealthComponentSchema            = "schema"             // Database schema migration level
+	HealthComponentMaterializedViews = "materialized_views" // Materialized views used by the queries
+	HealthComponentFileStorage       = "file_storage"       // File storage (bucket) reachability
+)
+
+// ComponentHealth is the result of the health check of a single component.
+type ComponentHealth struct {
+	Name    string        `json:"name"`            // Component name, e.g. database
+	Status  HealthStatus  `json:"status"`          // Component status
+	Latency time.Duration `json:"latency"`         // Time spent checking the component
+	Error   string        `json:"error,omitempty"` // Reason why the component is not usable
+}
+
+// Health is the result of the health check of the storage dependencies.
+type Health struct {
+	Status     HealthStatus      `json:"status"`     // HealthStatusOK only if all components are OK
+	CheckedAt  time.Time         `json:"checked_at"` // When the check was performed
+	Components []ComponentHealth `json:"components"` // Per component status
+}
@@ -0,0 +1,40 @@
+package models
+
+// ItemRankingMetric is the metric used to rank the rubricas.
+type ItemRankingMetric string
+
+const (
+	RankItemsByTotal      ItemRankingMetric = "total"         // Total paid in the period
+	RankItemsByRecipients ItemRankingMetric = "beneficiarios" // Number of distinct members who received the rubrica
+)
+
+// ItemRankingOpts are the options of a rubrica ranking.
+type ItemRankingOpts struct {
+	Metric   ItemRankingMetric `json:"metric"`
+	Period   Period            `json:"period"`
+	Limit    int               `json:"limit,omitempty"`    // Number of rubricas (10 if not set)
+	GroupBy  AgencyGrouping    `json:"group_by,omitempty"` // Restricts the ranking to the agencies of Group
+	Group    string            `json:"group,omitempty"`
+	Category *string           `json:"category,omitempty"` // e.g. "indenizações"
+}
+
+// ItemTrend contains the totals of a rubrica (sanitized name and category) in a period and
+// its monthly series, with all months of the period.
+type ItemTrend struct {
+	Item          string             `json:"item"`
+	Category      string             `json:"category"`
+	Total         float64            `json:"total"`
+	NumRecipients int                `json:"num_recipients"`
+	NumAgencies   int                `json:"num_agencies"`
+	Months        []ItemMonthlyTotal `json:"months"`
+}
+
+// ItemMonthlyTotal contains the totals of a rubrica in a month.
+type ItemMonthlyTotal struct {
+	Month         int      `json:"month"`
+	Year          int      `json:"year"`
+	Total         float64  `json:"total"`
+	NumRecipients int      `json:"num_recipients"`
+	NumAgencies   int      `json:"num_agencies"`
+	Change        *float64 `json:"change,omitempty"` // Relative change of the total from the previous month, nil if it was 0
+}
@@ -39,6 +39,7 @@ type AgencyMonthlyInfo struct {
 	ManualCollection  bool                   `json:"coleta_manual,omitempty"` // If the data was collected manually
 	Inconsistent      bool                   `json:"inconsistent"`            // If the data is inconsistent
 	Notices           string                 `json:"notices"`                 // If the data has notices
+	LastVerified      *timestamppb.Timestamp `json:"last_verified,omitempty"` // Last time an identical collection was stored (always UTC)
 }
 
 type Meta struct {
@@ -72,6 +73,7 @@ type MonthlyInfoVersion struct {
 
 // the GeneralMonthlyInfo is used to struct the agregation used to get the remuneration info from all angencies in a given month
 type GeneralMonthlyInfo struct {
+	Year               int         `json:"year,omitempty"`
 	Month              int         `json:"_id,omitempty"`
 	Count              int         `json:"count,omitempty"`               // Number of employees
 	BaseRemuneration   float64     `json:"base_remuneration,omitempty"`   //  Statistics (Max, Min, Median, Total)
@@ -113,3 +115,9 @@ type Remunerations struct {
 	NumOther     int    `json:"num_outras,omitempty"`
 	ZipUrl       string `json:"zip_url,omitempty"`
 }
+
+// MonthRange is an inclusive range of months (1-12) of a year.
+type MonthRange struct {
+	From int `json:"from,omitempty"`
+	To   int `json:"to,omitempty"`
+}
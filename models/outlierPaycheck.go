@@ -0,0 +1,65 @@
+package models
+
+import "fmt"
+
+// OutlierReason is the value of a paycheck that is far above its peers.
+type OutlierReason string
+
+const (
+	OutlierRemuneration OutlierReason = "remuneracao" // Gross remuneration of the paycheck
+	OutlierItem         OutlierReason = "rubrica"     // Total of a rubrica in the paycheck
+)
+
+// OutlierOpts are the options of the outlier paycheck detection. Zero fields take the default values.
+type OutlierOpts struct {
+	AgencyID  string   `json:"aid"`
+	Year      int      `json:"year"`
+	Month     *int     `json:"month,omitempty"`     // Checks a single month instead of the whole year
+	Items     []string `json:"items,omitempty"`     // Sanitized rubricas checked besides the remuneration
+	Threshold float64  `json:"threshold,omitempty"` // Minimum robust z-score of an outlier (3.5)
+	MinPeers  int      `json:"min_peers,omitempty"` // Minimum number of paychecks with the role in the month (5)
+}
+
+// Validate checks whether the agency is set and the year and month, if any, are valid.
+func (o OutlierOpts) Validate() error {
+	if o.AgencyID == "" {
+		return NewError(ErrInvalidInput, fmt.Errorf("agency cannot be empty"))
+	}
+	if o.Year <= 0 {
+		return NewError(ErrInvalidInput, fmt.Errorf("invalid year: %d", o.Year))
+	}
+	if o.Month != nil && (*o.Month < 1 || *o.Month > 12) {
+		return NewError(ErrInvalidInput, fmt.Errorf("invalid month: %d", *o.Month))
+	}
+	return nil
+}
+
+// PeerStats are the statistics of the paychecks with the same normalized role in the month.
+//
+// Scale is the divisor of the robust z-score. It is 1.4826 * MAD; if the MAD is 0, 1.2533 *
+// the mean absolute deviation; and, if that is also 0 (all values are the same), 5% of the
+// absolute median. Both constants make the scale estimate the standard deviation of normally
+// distributed values. The anomaly detection (see Anomaly) uses the same scale.
+type PeerStats struct {
+	NumPeers         int     `json:"num_peers"` // Including the paycheck itself
+	Median           float64 `json:"median"`
+	MAD              float64 `json:"mad"`                // Median absolute deviation
+	MeanAbsDeviation float64 `json:"mean_abs_deviation"` // Mean absolute deviation from the median
+	Scale            float64 `json:"scale"`              // Used in the score
+}
+
+// OutlierPaycheck is a paycheck whose remuneration or rubrica is far above the paychecks of
+// the members with the same normalized role in the month.
+type OutlierPaycheck struct {
+	PaycheckID int           `json:"id"`
+	AgencyID   string        `json:"aid"`
+	Month      int           `json:"month"`
+	Year       int           `json:"year"`
+	Name       string        `json:"name"`
+	Role       string        `json:"role"` // Normalized role
+	Reason     OutlierReason `json:"reason"`
+	Item       string        `json:"item,omitempty"` // Rubrica, when the reason is OutlierItem
+	Value      float64       `json:"value"`
+	Score      float64       `json:"score"` // Robust z-score: (value - Peers.Median) / Peers.Scale
+	Peers      PeerStats     `json:"peers"`
+}
@@ -1,18 +1,48 @@
 package models
 
+// PackageScope indicates which data a datapackage contains.
+type PackageScope string
+
+const (
+	PackageScopeMonthly PackageScope = "monthly" // Data from one agency in a month
+	PackageScopeAnnual  PackageScope = "annual"  // Data from one agency in a year
+	PackageScopeGroup   PackageScope = "group"   // Data from a group of agencies (e.g. jurisdiction)
+	PackageScopeDump    PackageScope = "dump"    // Full dump of the database
+)
+
 type Package struct {
-	AgencyID *string `json:"aid,omitempty"`
-	Month    *int    `json:"month,omitempty"`
-	Year     *int    `json:"year,omitempty"`
-	Group    *string `json:"group,omitempty"`
+	Key      string       `json:"key,omitempty"`   // Path of the package in the file storage, e.g. 'tjal/datapackage/tjal-2023.zip'
+	Scope    PackageScope `json:"scope,omitempty"` // If empty, it is inferred from the other fields
+	AgencyID *string      `json:"aid,omitempty"`
+	Month    *int         `json:"month,omitempty"`
+	Year     *int         `json:"year,omitempty"`
+	Group    *string      `json:"group,omitempty"`
 	Package  Backup
 }
 
+// InferScope returns the package scope, inferring it from the filled fields when it is not set.
+func (p Package) InferScope() PackageScope {
+	switch {
+	case p.Scope != "":
+		return p.Scope
+	case p.Group != nil:
+		return PackageScopeGroup
+	case p.AgencyID != nil && p.Month != nil:
+		return PackageScopeMonthly
+	case p.AgencyID != nil:
+		return PackageScopeAnnual
+	default:
+		return PackageScopeDump
+	}
+}
+
+// PackageFilterOpts Nil fields are not used to filter the packages.
 type PackageFilterOpts struct {
-	AgencyID *string `json:"aid,omitempty"`
-	Month    *int    `json:"month,omitempty"`
-	Year     *int    `json:"year,omitempty"`
-	Group    *string `json:"group,omitempty"`
+	AgencyID *string       `json:"aid,omitempty"`
+	Month    *int          `json:"month,omitempty"`
+	Year     *int          `json:"year,omitempty"`
+	Group    *string       `json:"group,omitempty"`
+	Scope    *PackageScope `json:"scope,omitempty"`
 }
 
 // Backup contains the URL to download a file and a hash to track if in the future will be changes in the file.
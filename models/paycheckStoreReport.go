@@ -0,0 +1,12 @@
+package models
+
+// PaycheckStoreReport A Struct containing how many paychecks and paycheck items were inserted,
+// updated and deleted when storing a batch of paychecks.
+type PaycheckStoreReport struct {
+	PaychecksInserted int `json:"paychecks_inserted"`
+	PaychecksUpdated  int `json:"paychecks_updated"`
+	PaychecksDeleted  int `json:"paychecks_deleted"`
+	ItemsInserted     int `json:"items_inserted"`
+	ItemsUpdated      int `json:"items_updated"`
+	ItemsDeleted      int `json:"items_deleted"`
+}
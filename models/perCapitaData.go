@@ -1,10 +1,16 @@
 package models
 
+// PerCapitaData contains the averages of the members of an agency in a year. Each member
+// is represented by their average monthly values; the percentiles are computed over these values.
 type PerCapitaData struct {
-	AgencyID           string  `json:"orgao,omitempty"`
-	Year               int     `json:"ano,omitempty"`
-	BaseRemuneration   float64 `json:"remuneracao_base,omitempty"`
-	OtherRemunerations float64 `json:"outras_remuneracoes,omitempty"`
-	Discounts          float64 `json:"descontos,omitempty"`
-	Remunerations      float64 `json:"remuneracoes,omitempty"`
+	AgencyID                      string       `json:"orgao,omitempty"`
+	Year                          int          `json:"ano,omitempty"`
+	BaseRemuneration              float64      `json:"remuneracao_base,omitempty"`
+	OtherRemunerations            float64      `json:"outras_remuneracoes,omitempty"`
+	Discounts                     float64      `json:"descontos,omitempty"`
+	Remunerations                 float64      `json:"remuneracoes,omitempty"`
+	BaseRemunerationPercentiles   *Percentiles `json:"remuneracao_base_percentis,omitempty"` // nil if not computed
+	OtherRemunerationsPercentiles *Percentiles `json:"outras_remuneracoes_percentis,omitempty"`
+	DiscountsPercentiles          *Percentiles `json:"descontos_percentis,omitempty"`
+	RemunerationsPercentiles      *Percentiles `json:"remuneracoes_percentis,omitempty"`
 }
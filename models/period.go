@@ -0,0 +1,43 @@
+package models
+
+import "fmt"
+
+// Period is an inclusive range of months, which may span several years.
+type Period struct {
+	FromMonth int `json:"from_month"`
+	FromYear  int `json:"from_year"`
+	ToMonth   int `json:"to_month"`
+	ToYear    int `json:"to_year"`
+}
+
+// LastMonths returns the period of n months ending at (and including) the given month,
+// e.g. LastMonths(3, 2023, 12) goes from 04/2022 to 03/2023.
+func LastMonths(month, year, n int) Period {
+	start := year*12 + month - 1 - (n - 1)
+	return Period{FromMonth: start%12 + 1, FromYear: start / 12, ToMonth: month, ToYear: year}
+}
+
+// Validate checks that both ends of the period are valid months and that the period
+// does not end before it starts.
+func (p Period) Validate() error {
+	if p.FromMonth < 1 || p.FromMonth > 12 || p.ToMonth < 1 || p.ToMonth > 12 {
+		return NewError(ErrInvalidInput, fmt.Errorf("invalid period %s: months must be between 1 and 12", p))
+	}
+	if p.ToYear*12+p.ToMonth < p.FromYear*12+p.FromMonth {
+		return NewError(ErrInvalidInput, fmt.Errorf("invalid period %s: end is before start", p))
+	}
+	return nil
+}
+
+func (p Period) String() string {
+	return fmt.Sprintf("%02d/%d-%02d/%d", p.FromMonth, p.FromYear, p.ToMonth, p.ToYear)
+}
+
+// Months returns the months of the period, in order.
+func (p Period) Months() []MonthYear {
+	var months []MonthYear
+	for m := p.FromYear*12 + p.FromMonth - 1; m <= p.ToYear*12+p.ToMonth-1; m++ {
+		months = append(months, MonthYear{Month: m%12 + 1, Year: m / 12})
+	}
+	return months
+}
@@ -0,0 +1,21 @@
+package models
+
+// PriceIndex is the value of a monthly price index series (e.g. the IPCA "número-índice") in a month.
+type PriceIndex struct {
+	Month int     `json:"month"`
+	Year  int     `json:"year"`
+	Value float64 `json:"value"`
+}
+
+// MonthYear identifies a month of a year.
+type MonthYear struct {
+	Month int `json:"month"`
+	Year  int `json:"year"`
+}
+
+// AggregationOpts are the options of the aggregation queries. Nil fields are not used.
+type AggregationOpts struct {
+	// DeflateTo makes the monetary values be deflated to the prices of the given month,
+	// using the stored price index series. Counts are not affected.
+	DeflateTo *MonthYear `json:"deflate_to,omitempty"`
+}
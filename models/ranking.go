@@ -0,0 +1,45 @@
+package models
+
+import "fmt"
+
+// RankingMetric is the metric used to rank the agencies.
+type RankingMetric string
+
+const (
+	RankByRemunerationPerCapita RankingMetric = "remuneracao_membro"   // Average remuneration per member (see GetRanking for the members considered)
+	RankByBenefitsShare         RankingMetric = "proporcao_beneficios" // Other remunerations / (base + other remunerations)
+	RankByTransparencyIndex     RankingMetric = "indice_transparencia"
+	RankByCompletenessIndex     RankingMetric = "indice_completude"
+	RankByEaseOfAccessIndex     RankingMetric = "indice_facilidade"
+)
+
+// RankingOpts are the options of a ranking. Nil fields are not used to filter the agencies.
+type RankingOpts struct {
+	Metric       RankingMetric `json:"metric"`
+	Year         int           `json:"year"`
+	Month        *int          `json:"month,omitempty"` // Rank a single month instead of the whole year
+	Jurisdiction *string       `json:"jurisdiction,omitempty"`
+	UF           *string       `json:"uf,omitempty"`
+}
+
+// Validate checks whether the metric is known and the month, if any, is between 1 and 12.
+func (o RankingOpts) Validate() error {
+	switch o.Metric {
+	case RankByRemunerationPerCapita, RankByBenefitsShare, RankByTransparencyIndex, RankByCompletenessIndex, RankByEaseOfAccessIndex:
+	default:
+		return NewError(ErrInvalidInput, fmt.Errorf("invalid ranking metric: %q", o.Metric))
+	}
+	if o.Month != nil && (*o.Month < 1 || *o.Month > 12) {
+		return NewError(ErrInvalidInput, fmt.Errorf("invalid month: %d", *o.Month))
+	}
+	return nil
+}
+
+// RankingEntry is the position of an agency in a ranking, from the highest value to the lowest.
+type RankingEntry struct {
+	Position   int     `json:"position"` // Agencies with the same value share the position
+	AgencyID   string  `json:"aid"`
+	Name       string  `json:"name,omitempty"`
+	Value      float64 `json:"value"`
+	Percentile float64 `json:"percentile"` // Percentage (0-100) of the other agencies with a lower value
+}
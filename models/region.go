@@ -0,0 +1,28 @@
+package models
+
+import "strings"
+
+// Macro-regions of Brazil.
+const (
+	RegionNorth       = "Norte"
+	RegionNortheast   = "Nordeste"
+	RegionCentralWest = "Centro-Oeste"
+	RegionSoutheast   = "Sudeste"
+	RegionSouth       = "Sul"
+)
+
+// Regions maps each federative unit (UF) to its macro-region.
+var Regions = map[string]string{
+	"AC": RegionNorth, "AM": RegionNorth, "AP": RegionNorth, "PA": RegionNorth, "RO": RegionNorth, "RR": RegionNorth, "TO": RegionNorth,
+	"AL": RegionNortheast, "BA": RegionNortheast, "CE": RegionNortheast, "MA": RegionNortheast, "PB": RegionNortheast,
+	"PE": RegionNortheast, "PI": RegionNortheast, "RN": RegionNortheast, "SE": RegionNortheast,
+	"DF": RegionCentralWest, "GO": RegionCentralWest, "MS": RegionCentralWest, "MT": RegionCentralWest,
+	"ES": RegionSoutheast, "MG": RegionSoutheast, "RJ": RegionSoutheast, "SP": RegionSoutheast,
+	"PR": RegionSouth, "RS": RegionSouth, "SC": RegionSouth,
+}
+
+// Region returns the macro-region of a federative unit, or an empty string if the UF
+// is unknown (e.g. federal agencies).
+func Region(uf string) string {
+	return Regions[strings.ToUpper(uf)]
+}
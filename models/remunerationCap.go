@@ -0,0 +1,29 @@
+package models
+
+// RemunerationCap is the constitutional remuneration cap (teto) in effect from a month on,
+// until the month of the next cap.
+type RemunerationCap struct {
+	Month int     `json:"month"`
+	Year  int     `json:"year"`
+	Value float64 `json:"value"`
+}
+
+// ItemTotal is the total value of a rubrica.
+type ItemTotal struct {
+	Item  string  `json:"item"`
+	Value float64 `json:"value"`
+}
+
+// CapExceedance reports how many members of an agency received more than the
+// remuneration cap in a month, and by how much.
+type CapExceedance struct {
+	AgencyID      string      `json:"aid"`
+	Month         int         `json:"month"`
+	Year          int         `json:"year"`
+	Cap           float64     `json:"cap"`             // Cap in effect in the month
+	NumMembers    int         `json:"num_members"`     // Number of paychecks in the month
+	NumAboveCap   int         `json:"num_above_cap"`   // Number of paychecks whose remuneration exceeded the cap
+	TotalExcess   float64     `json:"total_excess"`    // Sum of the amounts above the cap
+	AverageExcess float64     `json:"average_excess"`  // Average amount above the cap, among the members above it
+	Items         []ItemTotal `json:"items,omitempty"` // Other remunerations (R/O) of the members above the cap, by value (desc)
+}
@@ -0,0 +1,22 @@
+package models
+
+// RoleNormalization maps the roles (contracheques.funcao) matching an ILIKE pattern, such
+// as 'juiz de direito%', to a normalized role. When several patterns match a role, the
+// longest one is used.
+type RoleNormalization struct {
+	Pattern string `json:"pattern"`
+	Role    string `json:"role"`
+}
+
+// RemunerationGroup contains the totals of the paychecks of an agency in a period grouped
+// by normalized role or by workplace.
+type RemunerationGroup struct {
+	Group               string  `json:"group"`
+	NumMembers          int     `json:"num_members"`   // Number of distinct members
+	NumPaychecks        int     `json:"num_paychecks"` // Number of paychecks (one per member and month)
+	BaseRemuneration    float64 `json:"base_remuneration"`
+	OtherRemunerations  float64 `json:"other_remunerations"`
+	Discounts           float64 `json:"discounts"`
+	Remunerations       float64 `json:"remunerations"`
+	AverageRemuneration float64 `json:"average_remuneration"` // Average remuneration per paycheck
+}
@@ -20,11 +20,25 @@ type Summary struct {
 }
 
 // DataSummary A Struct containing data summary with statistics.
+// The percentiles are only computed from the stored paychecks (ComputeSummary). They are nil
+// in the summaries sent by the crawlers, and therefore in the stored collections returned by
+// GetOMA, GetMonthlyInfo, GetAnnualSummary, etc.
 type DataSummary struct {
-	Max     float64 `json:"maximo,omitempty"`
-	Min     float64 `json:"minimo,omitempty"`
-	Average float64 `json:"media,omitempty"`
-	Total   float64 `json:"total,omitempty"`
+	Max         float64      `json:"maximo,omitempty"`
+	Min         float64      `json:"minimo,omitempty"`
+	Average     float64      `json:"media,omitempty"`
+	Total       float64      `json:"total,omitempty"`
+	Percentiles *Percentiles `json:"percentis,omitempty"` // nil if not computed
+}
+
+// Percentiles A Struct containing the percentiles of a distribution (continuous, with interpolation).
+type Percentiles struct {
+	P10    float64 `json:"p10,omitempty"`
+	P25    float64 `json:"p25,omitempty"`
+	Median float64 `json:"mediana,omitempty"`
+	P75    float64 `json:"p75,omitempty"`
+	P90    float64 `json:"p90,omitempty"`
+	P99    float64 `json:"p99,omitempty"`
 }
 
 // DEPRECATED: The ItemSummary struct is deprecated
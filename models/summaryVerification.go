@@ -0,0 +1,25 @@
+package models
+
+// SummaryDiscrepancy is a summary field whose stored value differs from the value
+// computed from the stored paychecks.
+type SummaryDiscrepancy struct {
+	Field    string  `json:"field"`    // Field name, e.g. remuneracao_base.total or resumo_rubricas.ferias
+	Stored   float64 `json:"stored"`   // Value in the stored summary (sent by the crawler)
+	Computed float64 `json:"computed"` // Value computed from the paychecks
+}
+
+// SummaryVerification is the result of the comparison between the stored summary of an
+// agency-month and the summary computed from its paychecks.
+type SummaryVerification struct {
+	AgencyID      string               `json:"aid"`
+	Month         int                  `json:"month"`
+	Year          int                  `json:"year"`
+	Stored        *Summary             `json:"stored"`
+	Computed      *Summary             `json:"computed"`
+	Discrepancies []SummaryDiscrepancy `json:"discrepancies,omitempty"`
+}
+
+// OK reports whether the stored summary matches the computed one.
+func (v SummaryVerification) OK() bool {
+	return len(v.Discrepancies) == 0
+}
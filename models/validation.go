@@ -0,0 +1,40 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError is a validation error of a single field.
+type FieldError struct {
+	Field   string `json:"field"`   // Field path, e.g. paychecks[2].mes
+	Message string `json:"message"` // What is wrong with the field
+}
+
+// ValidationError aggregates all field errors found while validating the input of a write.
+// It is returned wrapped in an *Error of kind ErrInvalidInput.
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+// Add adds a field error.
+func (e *ValidationError) Add(field, format string, args ...any) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns nil if there are no field errors or the validation error classified
+// as ErrInvalidInput otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return NewError(ErrInvalidInput, e)
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dadosjusbr/storage/models"
+)
+
+// parsePriceIndex reads a monthly price index series from a CSV file with the columns
+// year, month and value (e.g. the IPCA "número-índice" published by IBGE), one month
+// per line. Both comma and semicolon separators are accepted, and an optional header
+// line is skipped. Values may use the Brazilian format (decimal comma and optional
+// dots as thousands separators) when the separator is a semicolon.
+//
+//	ano;mes;valor
+//	2023;1;6474,09
+//	2023;2;6.524,32
+func parsePriceIndex(r io.Reader) ([]models.PriceIndex, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading price index: %w", err)
+	}
+	reader := csv.NewReader(strings.NewReader(string(content)))
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	if firstLine, _, _ := strings.Cut(string(content), "\n"); strings.Contains(firstLine, ";") {
+		reader.Comma = ';'
+	}
+
+	var series []models.PriceIndex
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, models.NewError(models.ErrInvalidInput, fmt.Errorf("error parsing price index: %w", err))
+		}
+		year, errYear := strconv.Atoi(record[0])
+		if line == 1 && errYear != nil {
+			continue // Header
+		}
+		month, errMonth := strconv.Atoi(record[1])
+		value, errValue := parseDecimal(record[2])
+		if err := errors.Join(errYear, errMonth, errValue); err != nil {
+			return nil, models.NewError(models.ErrInvalidInput, fmt.Errorf("error parsing price index at line %d: %w", line, err))
+		}
+		series = append(series, models.PriceIndex{Year: year, Month: month, Value: value})
+	}
+	return series, nil
+}
+
+// parseDecimal parses a number that may be in the Brazilian format: when it has a
+// decimal comma, dots are thousands separators (e.g. "6.474,09").
+func parseDecimal(s string) (float64, error) {
+	if strings.Contains(s, ",") {
+		s = strings.Replace(strings.ReplaceAll(s, ".", ""), ",", ".", 1)
+	}
+	return strconv.ParseFloat(s, 64)
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dadosjusbr/storage/models"
+	"github.com/dadosjusbr/storage/repo/database/dto"
+	"gorm.io/gorm"
+)
+
+// agencyGroupColumn retorna a expressão SQL, sobre a tabela orgaos, do atributo usado no agrupamento.
+func agencyGroupColumn(groupBy models.AgencyGrouping) (string, error) {
+	switch groupBy {
+	case models.GroupByJurisdiction:
+		return "COALESCE(orgaos.jurisdicao, '')", nil
+	case models.GroupByUF:
+		return "COALESCE(UPPER(orgaos.uf), '')", nil
+	case models.GroupByEntity:
+		return "COALESCE(orgaos.entidade, '')", nil
+	case models.GroupByRegion:
+		ufs := make([]string, 0, len(models.Regions))
+		for uf := range models.Regions {
+			ufs = append(ufs, uf)
+		}
+		sort.Strings(ufs)
+		var b strings.Builder
+		b.WriteString("CASE UPPER(orgaos.uf)")
+		for _, uf := range ufs {
+			fmt.Fprintf(&b, " WHEN '%s' THEN '%s'", uf, models.Regions[uf])
+		}
+		b.WriteString(" ELSE '' END")
+		return b.String(), nil
+	}
+	return "", models.NewError(models.ErrInvalidInput, fmt.Errorf("invalid agency grouping: %q", groupBy))
+}
+
+// currentCollections filtra as coletas atuais e sem erro, no período, junto com os dados do órgão.
+func currentCollections(db *gorm.DB, period models.Period) *gorm.DB {
+	db = db.Joins("JOIN orgaos ON orgaos.id = coletas.id_orgao")
+	db = db.Where("coletas.atual = TRUE AND (coletas.procinfo IS NULL OR coletas.procinfo::text = 'null')")
+	return inPeriod(db, "coletas", period)
+}
+
+// GetGroupedMonthlyInfos retorna os totais mensais no período, agrupados por um atributo
+// dos órgãos (jurisdição, UF, região ou entidade) e ordenados por grupo, ano e mês.
+// Órgãos sem o atributo (ex.: órgãos federais, que não têm UF) ficam no grupo "".
+func (p *PostgresDB) GetGroupedMonthlyInfos(groupBy models.AgencyGrouping, period models.Period, opts ...models.AggregationOpts) ([]models.GroupedMonthlyInfo, error) {
+	group, err := agencyGroupColumn(groupBy)
+	if err != nil {
+		return nil, err
+	}
+	if err := period.Validate(); err != nil {
+		return nil, err
+	}
+	d, err := p.newDeflation(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.check(currentCollections(p.reader().Model(&dto.AgencyMonthlyInfoDTO{}), period), "coletas"); err != nil {
+		return nil, err
+	}
+	f := d.factor("coletas")
+
+	query := fmt.Sprintf(`
+		%[1]s AS grupo,
+		coletas.ano,
+		coletas.mes,
+		COUNT(DISTINCT coletas.id_orgao) AS num_orgaos,
+		SUM((sumario -> 'membros')::text::int) AS num_membros,
+		SUM(CAST(sumario -> 'remuneracao_base' ->> 'total' AS DECIMAL) * %[2]s) AS remuneracao_base,
+		SUM(CAST(sumario -> 'outras_remuneracoes' ->> 'total' AS DECIMAL) * %[2]s) AS outras_remuneracoes,
+		SUM(CAST(sumario -> 'descontos' ->> 'total' AS DECIMAL) * %[2]s) AS descontos,
+		SUM(CAST(sumario -> 'remuneracoes' ->> 'total' AS DECIMAL) * %[2]s) AS remuneracoes`, group, f)
+	var dtoGmis []dto.GroupedMonthlyInfoDTO
+	m := currentCollections(p.reader().Model(&dto.AgencyMonthlyInfoDTO{}), period).Select(query)
+	m = m.Group("grupo, coletas.ano, coletas.mes").Order("grupo, coletas.ano, coletas.mes")
+	if err := m.Scan(&dtoGmis).Error; err != nil {
+		return nil, fmt.Errorf("error getting grouped monthly info: %w", classify(err))
+	}
+
+	var dtoItems []dto.GroupedMonthlyItemTotalDTO
+	m = p.reader().Model(&dto.ItemSummaryDTO{}).Select(fmt.Sprintf("%s AS grupo, coletas.ano, coletas.mes, resumo_rubricas.rubrica, SUM(resumo_rubricas.valor * %s) AS valor", group, d.factor("resumo_rubricas")))
+	m = m.Joins(`JOIN coletas ON coletas.id_orgao = resumo_rubricas.id_orgao
+				 AND coletas.mes = resumo_rubricas.mes
+				 AND coletas.ano = resumo_rubricas.ano`)
+	m = currentCollections(m, period)
+	m = m.Group("grupo, coletas.ano, coletas.mes, resumo_rubricas.rubrica")
+	if err := m.Scan(&dtoItems).Error; err != nil {
+		return nil, fmt.Errorf("error getting grouped item totals: %w", classify(err))
+	}
+
+	// Todas as rubricas do resultado aparecem em todos os grupos/meses, com 0 quando não há dados.
+	type key struct {
+		group       string
+		year, month int
+	}
+	items := make(map[string]bool)
+	totals := make(map[key]models.ItemSummary)
+	for _, t := range dtoItems {
+		items[t.Item] = true
+		k := key{t.Group, t.Year, t.Month}
+		if totals[k] == nil {
+			totals[k] = make(models.ItemSummary)
+		}
+		totals[k][t.Item] = t.Value
+	}
+
+	var gmis []models.GroupedMonthlyInfo
+	for _, dtoGmi := range dtoGmis {
+		summary := make(models.ItemSummary, len(items))
+		for item := range items {
+			summary[item] = totals[key{dtoGmi.Group, dtoGmi.Year, dtoGmi.Month}][item]
+		}
+		gmis = append(gmis, *dtoGmi.ConvertToModel(summary))
+	}
+	return gmis, nil
+}
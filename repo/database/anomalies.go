@@ -0,0 +1,56 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/dadosjusbr/storage/models"
+	"github.com/dadosjusbr/storage/repo/database/dto"
+	"gorm.io/gorm"
+)
+
+// StoreAnomalies substitui as anomalias do órgão no período pelas anomalias informadas, de
+// forma que uma nova detecção remove as anomalias que deixaram de existir.
+func (p *PostgresDB) StoreAnomalies(agencyID string, period models.Period, anomalies []models.Anomaly) error {
+	if err := period.Validate(); err != nil {
+		return err
+	}
+	defer p.markWrite()
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		if err := inPeriod(tx.Where("id_orgao = ?", agencyID), "anomalias", period).Delete(&dto.AnomalyDTO{}).Error; err != nil {
+			return fmt.Errorf("error deleting 'anomalias': %w", err)
+		}
+		if len(anomalies) == 0 {
+			return nil
+		}
+		dtoAnomalies := make([]dto.AnomalyDTO, 0, len(anomalies))
+		for _, a := range anomalies {
+			dtoAnomalies = append(dtoAnomalies, *dto.NewAnomalyDTO(a))
+		}
+		if err := tx.Model(dto.AnomalyDTO{}).CreateInBatches(&dtoAnomalies, 500).Error; err != nil {
+			return fmt.Errorf("error inserting 'anomalias': %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error performing transaction: %w", classify(err))
+	}
+	return nil
+}
+
+// GetAnomalies retorna as anomalias armazenadas do órgão no período, ordenadas por ano, mês,
+// métrica e rubrica.
+func (p *PostgresDB) GetAnomalies(agencyID string, period models.Period) ([]models.Anomaly, error) {
+	if err := period.Validate(); err != nil {
+		return nil, err
+	}
+	var dtoAnomalies []dto.AnomalyDTO
+	m := inPeriod(p.reader().Model(&dto.AnomalyDTO{}).Where("id_orgao = ?", agencyID), "anomalias", period)
+	if err := m.Order("ano, mes, metrica, rubrica").Find(&dtoAnomalies).Error; err != nil {
+		return nil, fmt.Errorf("error getting anomalies: %w", classify(err))
+	}
+	var anomalies []models.Anomaly
+	for _, a := range dtoAnomalies {
+		anomalies = append(anomalies, *a.ConvertToModel())
+	}
+	return anomalies, nil
+}
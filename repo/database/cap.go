@@ -0,0 +1,125 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dadosjusbr/storage/models"
+	"github.com/dadosjusbr/storage/repo/database/dto"
+	"gorm.io/gorm/clause"
+)
+
+// StoreRemunerationCaps armazena (ou atualiza) os tetos remuneratórios.
+func (p *PostgresDB) StoreRemunerationCaps(caps []models.RemunerationCap) error {
+	if len(caps) == 0 {
+		return nil
+	}
+	defer p.markWrite()
+	dtoCaps := make([]dto.RemunerationCapDTO, 0, len(caps))
+	for _, c := range caps {
+		dtoCaps = append(dtoCaps, *dto.NewRemunerationCapDTO(c))
+	}
+	if err := p.db.Model(dto.RemunerationCapDTO{}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ano"}, {Name: "mes"}},
+		UpdateAll: true,
+	}).Create(&dtoCaps).Error; err != nil {
+		return fmt.Errorf("error inserting 'teto_remuneratorio': %w", classify(err))
+	}
+	return nil
+}
+
+// GetRemunerationCaps retorna os tetos remuneratórios, ordenados por ano e mês.
+func (p *PostgresDB) GetRemunerationCaps() ([]models.RemunerationCap, error) {
+	var dtoCaps []dto.RemunerationCapDTO
+	if err := p.reader().Model(&dto.RemunerationCapDTO{}).Order("ano, mes").Find(&dtoCaps).Error; err != nil {
+		return nil, fmt.Errorf("error getting remuneration caps: %w", classify(err))
+	}
+	var caps []models.RemunerationCap
+	for _, c := range dtoCaps {
+		caps = append(caps, *c.ConvertToModel())
+	}
+	return caps, nil
+}
+
+// paychecksWithCap seleciona os contracheques do período (e dos órgãos, se informados) junto
+// com o teto em vigor no mês de cada um: o último teto com mês/ano menor ou igual ao do
+// contracheque. Contracheques anteriores ao primeiro teto cadastrado são ignorados.
+const paychecksWithCap = `
+	SELECT c.orgao, c.ano, c.mes, c.id, c.remuneracao, t.valor AS teto
+	FROM contracheques c
+	JOIN LATERAL (
+		SELECT valor FROM teto_remuneratorio t
+		WHERE (t.ano, t.mes) <= (c.ano, c.mes)
+		ORDER BY t.ano DESC, t.mes DESC
+		LIMIT 1
+	) t ON TRUE
+	WHERE (c.ano, c.mes) >= (?, ?) AND (c.ano, c.mes) <= (?, ?) %s`
+
+// GetCapExceedances retorna, para cada órgão/mês do período, quantos membros receberam acima
+// do teto remuneratório, o excedente total e médio e as rubricas (outras remunerações) que
+// compõem a remuneração dos membros acima do teto. Se nenhum órgão for informado, todos são
+// considerados.
+func (p *PostgresDB) GetCapExceedances(agencies []models.Agency, period models.Period) ([]models.CapExceedance, error) {
+	if err := period.Validate(); err != nil {
+		return nil, err
+	}
+	args := []interface{}{period.FromYear, period.FromMonth, period.ToYear, period.ToMonth}
+	filter := ""
+	if len(agencies) > 0 {
+		ids := make([]string, 0, len(agencies))
+		for _, agency := range agencies {
+			ids = append(ids, strings.ToLower(agency.ID))
+		}
+		filter = "AND c.orgao IN ?"
+		args = append(args, ids)
+	}
+	capped := fmt.Sprintf(paychecksWithCap, filter)
+	db := p.reader()
+
+	var dtoExceedances []dto.CapExceedanceDTO
+	query := `WITH c AS (` + capped + `)
+		SELECT orgao, ano, mes, MAX(teto) AS teto, COUNT(*) AS num_membros,
+			COUNT(*) FILTER (WHERE remuneracao > teto) AS num_acima_teto,
+			COALESCE(SUM(remuneracao - teto) FILTER (WHERE remuneracao > teto), 0) AS excedente_total
+		FROM c
+		GROUP BY orgao, ano, mes
+		ORDER BY orgao, ano, mes`
+	if err := db.Raw(query, args...).Scan(&dtoExceedances).Error; err != nil {
+		return nil, fmt.Errorf("error getting cap exceedances: %w", classify(err))
+	}
+
+	// Rubricas não identificadas (sem item sanitizado) são agregadas em 'outras', como no resumo.
+	var dtoItems []dto.AgencyMonthItemTotalDTO
+	query = `WITH c AS (` + capped + `)
+		SELECT r.orgao, r.ano, r.mes, COALESCE(r.item_sanitizado, 'outras') AS rubrica, SUM(r.valor) AS valor
+		FROM remuneracoes r
+		JOIN c ON c.orgao = r.orgao AND c.ano = r.ano AND c.mes = r.mes AND c.id = r.id_contracheque
+		WHERE c.remuneracao > c.teto AND r.tipo = 'R/O'
+		GROUP BY r.orgao, r.ano, r.mes, rubrica`
+	if err := db.Raw(query, args...).Scan(&dtoItems).Error; err != nil {
+		return nil, fmt.Errorf("error getting items above the cap: %w", classify(err))
+	}
+	type key struct {
+		agency      string
+		year, month int
+	}
+	items := make(map[key][]models.ItemTotal)
+	for _, i := range dtoItems {
+		k := key{i.AgencyID, i.Year, i.Month}
+		items[k] = append(items[k], models.ItemTotal{Item: i.Item, Value: i.Value})
+	}
+
+	var exceedances []models.CapExceedance
+	for _, e := range dtoExceedances {
+		its := items[key{e.AgencyID, e.Year, e.Month}]
+		sort.Slice(its, func(i, j int) bool {
+			if its[i].Value != its[j].Value {
+				return its[i].Value > its[j].Value
+			}
+			return its[i].Item < its[j].Item
+		})
+		exceedances = append(exceedances, *e.ConvertToModel(its))
+	}
+	return exceedances, nil
+}
@@ -0,0 +1,173 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dadosjusbr/storage/models"
+	"github.com/dadosjusbr/storage/repo/database/dto"
+)
+
+// Origens dos meses de um órgão na consulta de cobertura.
+const (
+	coverageMonitored    = "monitorado" // Há coleta, de qualquer revisão e com ou sem erro
+	coverageCollection   = "coleta"
+	coveragePaychecks    = "contracheques"
+	coverageZip          = "zip"
+	coverageInconsistent = "inconsistente"
+)
+
+// coverageMonths retorna, para cada órgão, os meses presentes em cada origem da cobertura.
+const coverageMonths = `
+	SELECT * FROM (
+		SELECT DISTINCT id_orgao AS orgao, ano, mes, '` + coverageMonitored + `' AS origem FROM coletas
+		UNION ALL
+		SELECT id_orgao, ano, mes, '` + coverageCollection + `' FROM coletas
+		WHERE atual = TRUE AND (procinfo IS NULL OR procinfo::text = 'null')
+		UNION ALL
+		SELECT DISTINCT orgao, ano, mes, '` + coveragePaychecks + `' FROM contracheques
+		UNION ALL
+		SELECT id_orgao, ano, mes, '` + coverageZip + `' FROM remuneracoes_zips
+		UNION ALL
+		SELECT id_orgao, ano, mes, '` + coverageInconsistent + `' FROM orgao_mes_ano_inconsistentes
+		WHERE inconsistente = TRUE
+	) m %s`
+
+// GetCoverage retorna a cobertura dos dados de cada órgão (todos, se nenhum for informado),
+// ordenada por órgão: para cada mês, do primeiro mês monitorado (ou do primeiro status de
+// coleta com timestamp, se for anterior) até o mês informado, se há coleta atual sem erro,
+// contracheques, zip de remunerações e remunerações inconsistentes. Meses sem coleta em que o
+// status de coleta vigente indica que o órgão não publica os dados não são faltantes. A
+// inconsistência vem da view 'orgao_mes_ano_inconsistentes'.
+func (p *PostgresDB) GetCoverage(agencies []models.Agency, until models.MonthYear) ([]models.AgencyCoverage, error) {
+	if until.Month < 1 || until.Month > 12 {
+		return nil, models.NewError(models.ErrInvalidInput, fmt.Errorf("invalid month: %d", until.Month))
+	}
+	var args []interface{}
+	filter := ""
+	m := p.reader().Model(&dto.AgencyDTO{})
+	if len(agencies) > 0 {
+		ids := make([]string, 0, len(agencies))
+		for _, agency := range agencies {
+			ids = append(ids, strings.ToLower(agency.ID))
+		}
+		filter = "WHERE m.orgao IN ?"
+		args = append(args, ids)
+		m = m.Where("id IN ?", ids)
+	}
+	var dtoAgencies []dto.AgencyDTO
+	if err := m.Order("id").Find(&dtoAgencies).Error; err != nil {
+		return nil, fmt.Errorf("error getting agencies: %w", classify(err))
+	}
+	var dtoMonths []dto.CoverageMonthDTO
+	if err := p.reader().Raw(fmt.Sprintf(coverageMonths, filter), args...).Scan(&dtoMonths).Error; err != nil {
+		return nil, fmt.Errorf("error getting coverage: %w", classify(err))
+	}
+
+	index := func(month, year int) int { return year*12 + month - 1 }
+	first := make(map[string]int)
+	cells := make(map[string]map[int]*models.CoverageCell)
+	for _, dm := range dtoMonths {
+		i := index(dm.Month, dm.Year)
+		if dm.Source == coverageMonitored {
+			if f, ok := first[dm.AgencyID]; !ok || i < f {
+				first[dm.AgencyID] = i
+			}
+		}
+		if cells[dm.AgencyID] == nil {
+			cells[dm.AgencyID] = make(map[int]*models.CoverageCell)
+		}
+		cell := cells[dm.AgencyID][i]
+		if cell == nil {
+			cell = &models.CoverageCell{Month: dm.Month, Year: dm.Year}
+			cells[dm.AgencyID][i] = cell
+		}
+		switch dm.Source {
+		case coverageCollection:
+			cell.HasCollection = true
+		case coveragePaychecks:
+			cell.HasPaychecks = true
+		case coverageZip:
+			cell.HasRemunerationZip = true
+		case coverageInconsistent:
+			cell.Inconsistent = true
+		}
+	}
+
+	var coverage []models.AgencyCoverage
+	for _, dtoAgency := range dtoAgencies {
+		agency, err := dtoAgency.ConvertToModel()
+		if err != nil {
+			return nil, fmt.Errorf("error converting agency dto to model: %w", err)
+		}
+		ac := models.AgencyCoverage{AgencyID: agency.ID, Collecting: latestCollecting(agency.Collecting)}
+		statuses := timedCollecting(agency.Collecting)
+		statusMonth := func(c models.Collecting) int {
+			t := time.Unix(*c.Timestamp, 0).UTC()
+			return index(int(t.Month()), t.Year())
+		}
+		f, ok := first[agency.ID]
+		if len(statuses) > 0 && (!ok || statusMonth(statuses[0]) < f) {
+			f, ok = statusMonth(statuses[0]), true
+		}
+		if ok {
+			var status *models.Collecting
+			next := 0
+			for i := f; i <= index(until.Month, until.Year); i++ {
+				for next < len(statuses) && statusMonth(statuses[next]) <= i {
+					status = &statuses[next]
+					next++
+				}
+				cell := models.CoverageCell{Month: i%12 + 1, Year: i / 12}
+				if c := cells[agency.ID][i]; c != nil {
+					cell = *c
+				}
+				if !cell.HasCollection && status != nil && !status.Collecting {
+					cell.NotCollecting = true
+					cell.NotCollectingReasons = status.Description
+				}
+				cell.Missing = !cell.HasCollection && !cell.NotCollecting
+				if cell.Missing {
+					ac.NumMissing++
+				}
+				ac.Cells = append(ac.Cells, cell)
+			}
+		}
+		coverage = append(coverage, ac)
+	}
+	return coverage, nil
+}
+
+// latestCollecting retorna o status de coleta mais recente: o de maior timestamp ou, se
+// nenhum tiver timestamp, o último da lista.
+func latestCollecting(collecting []models.Collecting) *models.Collecting {
+	var latest *models.Collecting
+	for i, c := range collecting {
+		switch {
+		case latest == nil:
+			latest = &collecting[i]
+		case c.Timestamp != nil:
+			if latest.Timestamp == nil || *c.Timestamp >= *latest.Timestamp {
+				latest = &collecting[i]
+			}
+		case latest.Timestamp == nil:
+			latest = &collecting[i]
+		}
+	}
+	return latest
+}
+
+// timedCollecting retorna os status de coleta com timestamp, ordenados pelo timestamp. Status
+// sem timestamp não podem ser situados no tempo e são ignorados.
+func timedCollecting(collecting []models.Collecting) []models.Collecting {
+	var timed []models.Collecting
+	for _, c := range collecting {
+		if c.Timestamp != nil {
+			timed = append(timed, c)
+		}
+	}
+	sort.SliceStable(timed, func(i, j int) bool { return *timed[i].Timestamp < *timed[j].Timestamp })
+	return timed
+}
@@ -5,6 +5,7 @@
 package database
 
 import (
+	context "context"
 	reflect "reflect"
 
 	models "github.com/dadosjusbr/storage/models"
@@ -34,6 +35,35 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// BulkStorePaychecks mocks base method.
+func (m *MockInterface) BulkStorePaychecks(p []models.Paycheck, r []models.PaycheckItem, rp []models.RetroactivePayments) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkStorePaychecks", p, r, rp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkStorePaychecks indicates an expected call of BulkStorePaychecks.
+func (mr *MockInterfaceMockRecorder) BulkStorePaychecks(p, r, rp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkStorePaychecks", reflect.TypeOf((*MockInterface)(nil).BulkStorePaychecks), p, r, rp)
+}
+
+// ComputeSummary mocks base method.
+func (m *MockInterface) ComputeSummary(agency string, month, year int) (*models.Summary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ComputeSummary", agency, month, year)
+	ret0, _ := ret[0].(*models.Summary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ComputeSummary indicates an expected call of ComputeSummary.
+func (mr *MockInterfaceMockRecorder) ComputeSummary(agency, month, year interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ComputeSummary", reflect.TypeOf((*MockInterface)(nil).ComputeSummary), agency, month, year)
+}
+
 // Connect mocks base method.
 func (m *MockInterface) Connect() error {
 	m.ctrl.T.Helper()
@@ -48,6 +78,21 @@ func (mr *MockInterfaceMockRecorder) Connect() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockInterface)(nil).Connect))
 }
 
+// DeleteMonthlyData mocks base method.
+func (m *MockInterface) DeleteMonthlyData(agency string, month, year int) (*models.DeletionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMonthlyData", agency, month, year)
+	ret0, _ := ret[0].(*models.DeletionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMonthlyData indicates an expected call of DeleteMonthlyData.
+func (mr *MockInterfaceMockRecorder) DeleteMonthlyData(agency, month, year interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMonthlyData", reflect.TypeOf((*MockInterface)(nil).DeleteMonthlyData), agency, month, year)
+}
+
 // Disconnect mocks base method.
 func (m *MockInterface) Disconnect() error {
 	m.ctrl.T.Helper()
@@ -138,48 +183,108 @@ func (mr *MockInterfaceMockRecorder) GetAllAgencyCollection(agency interface{})
 }
 
 // GetAnnualSummary mocks base method.
-func (m *MockInterface) GetAnnualSummary(agency string) ([]models.AnnualSummary, error) {
+func (m *MockInterface) GetAnnualSummary(agency string, opts ...models.AggregationOpts) ([]models.AnnualSummary, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAnnualSummary", agency)
+	varargs := []interface{}{agency}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAnnualSummary", varargs...)
 	ret0, _ := ret[0].([]models.AnnualSummary)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAnnualSummary indicates an expected call of GetAnnualSummary.
-func (mr *MockInterfaceMockRecorder) GetAnnualSummary(agency interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) GetAnnualSummary(agency interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{agency}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnnualSummary", reflect.TypeOf((*MockInterface)(nil).GetAnnualSummary), varargs...)
+}
+
+// GetAnomalies mocks base method.
+func (m *MockInterface) GetAnomalies(agencyID string, period models.Period) ([]models.Anomaly, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnomalies", agencyID, period)
+	ret0, _ := ret[0].([]models.Anomaly)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnomalies indicates an expected call of GetAnomalies.
+func (mr *MockInterfaceMockRecorder) GetAnomalies(agencyID, period interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnnualSummary", reflect.TypeOf((*MockInterface)(nil).GetAnnualSummary), agency)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnomalies", reflect.TypeOf((*MockInterface)(nil).GetAnomalies), agencyID, period)
 }
 
 // GetAveragePerAgency mocks base method.
-func (m *MockInterface) GetAveragePerAgency(year int) ([]models.PerCapitaData, error) {
+func (m *MockInterface) GetAveragePerAgency(year int, opts ...models.AggregationOpts) ([]models.PerCapitaData, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAveragePerAgency", year)
+	varargs := []interface{}{year}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAveragePerAgency", varargs...)
 	ret0, _ := ret[0].([]models.PerCapitaData)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAveragePerAgency indicates an expected call of GetAveragePerAgency.
-func (mr *MockInterfaceMockRecorder) GetAveragePerAgency(year interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) GetAveragePerAgency(year interface{}, opts ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAveragePerAgency", reflect.TypeOf((*MockInterface)(nil).GetAveragePerAgency), year)
+	varargs := append([]interface{}{year}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAveragePerAgency", reflect.TypeOf((*MockInterface)(nil).GetAveragePerAgency), varargs...)
 }
 
 // GetAveragePerCapita mocks base method.
-func (m *MockInterface) GetAveragePerCapita(agency string, year int) (*models.PerCapitaData, error) {
+func (m *MockInterface) GetAveragePerCapita(agency string, year int, opts ...models.AggregationOpts) (*models.PerCapitaData, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAveragePerCapita", agency, year)
+	varargs := []interface{}{agency, year}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAveragePerCapita", varargs...)
 	ret0, _ := ret[0].(*models.PerCapitaData)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAveragePerCapita indicates an expected call of GetAveragePerCapita.
-func (mr *MockInterfaceMockRecorder) GetAveragePerCapita(agency, year interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) GetAveragePerCapita(agency, year interface{}, opts ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAveragePerCapita", reflect.TypeOf((*MockInterface)(nil).GetAveragePerCapita), agency, year)
+	varargs := append([]interface{}{agency, year}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAveragePerCapita", reflect.TypeOf((*MockInterface)(nil).GetAveragePerCapita), varargs...)
+}
+
+// GetCapExceedances mocks base method.
+func (m *MockInterface) GetCapExceedances(agencies []models.Agency, period models.Period) ([]models.CapExceedance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCapExceedances", agencies, period)
+	ret0, _ := ret[0].([]models.CapExceedance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCapExceedances indicates an expected call of GetCapExceedances.
+func (mr *MockInterfaceMockRecorder) GetCapExceedances(agencies, period interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapExceedances", reflect.TypeOf((*MockInterface)(nil).GetCapExceedances), agencies, period)
+}
+
+// GetCoverage mocks base method.
+func (m *MockInterface) GetCoverage(agencies []models.Agency, until models.MonthYear) ([]models.AgencyCoverage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoverage", agencies, until)
+	ret0, _ := ret[0].([]models.AgencyCoverage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCoverage indicates an expected call of GetCoverage.
+func (mr *MockInterfaceMockRecorder) GetCoverage(agencies, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoverage", reflect.TypeOf((*MockInterface)(nil).GetCoverage), agencies, until)
 }
 
 // GetFirstDateWithMonthlyInfo mocks base method.
@@ -228,6 +333,46 @@ func (mr *MockInterfaceMockRecorder) GetGeneralMonthlyInfosFromYear(year interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeneralMonthlyInfosFromYear", reflect.TypeOf((*MockInterface)(nil).GetGeneralMonthlyInfosFromYear), year)
 }
 
+// GetGeneralMonthlyInfosInPeriod mocks base method.
+func (m *MockInterface) GetGeneralMonthlyInfosInPeriod(period models.Period, opts ...models.AggregationOpts) ([]models.GeneralMonthlyInfo, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{period}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetGeneralMonthlyInfosInPeriod", varargs...)
+	ret0, _ := ret[0].([]models.GeneralMonthlyInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeneralMonthlyInfosInPeriod indicates an expected call of GetGeneralMonthlyInfosInPeriod.
+func (mr *MockInterfaceMockRecorder) GetGeneralMonthlyInfosInPeriod(period interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{period}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeneralMonthlyInfosInPeriod", reflect.TypeOf((*MockInterface)(nil).GetGeneralMonthlyInfosInPeriod), varargs...)
+}
+
+// GetGroupedMonthlyInfos mocks base method.
+func (m *MockInterface) GetGroupedMonthlyInfos(groupBy models.AgencyGrouping, period models.Period, opts ...models.AggregationOpts) ([]models.GroupedMonthlyInfo, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{groupBy, period}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetGroupedMonthlyInfos", varargs...)
+	ret0, _ := ret[0].([]models.GroupedMonthlyInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupedMonthlyInfos indicates an expected call of GetGroupedMonthlyInfos.
+func (mr *MockInterfaceMockRecorder) GetGroupedMonthlyInfos(groupBy, period interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{groupBy, period}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupedMonthlyInfos", reflect.TypeOf((*MockInterface)(nil).GetGroupedMonthlyInfos), varargs...)
+}
+
 // GetIndexInformation mocks base method.
 func (m *MockInterface) GetIndexInformation(name string, month, year int) (map[string][]models.IndexInformation, error) {
 	m.ctrl.T.Helper()
@@ -243,6 +388,26 @@ func (mr *MockInterfaceMockRecorder) GetIndexInformation(name, month, year inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIndexInformation", reflect.TypeOf((*MockInterface)(nil).GetIndexInformation), name, month, year)
 }
 
+// GetItemTrends mocks base method.
+func (m *MockInterface) GetItemTrends(opts models.ItemRankingOpts, aggOpts ...models.AggregationOpts) ([]models.ItemTrend, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{opts}
+	for _, a := range aggOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetItemTrends", varargs...)
+	ret0, _ := ret[0].([]models.ItemTrend)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemTrends indicates an expected call of GetItemTrends.
+func (mr *MockInterfaceMockRecorder) GetItemTrends(opts interface{}, aggOpts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{opts}, aggOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemTrends", reflect.TypeOf((*MockInterface)(nil).GetItemTrends), varargs...)
+}
+
 // GetLastDateWithMonthlyInfo mocks base method.
 func (m *MockInterface) GetLastDateWithMonthlyInfo() (int, int, error) {
 	m.ctrl.T.Helper()
@@ -259,19 +424,54 @@ func (mr *MockInterfaceMockRecorder) GetLastDateWithMonthlyInfo() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastDateWithMonthlyInfo", reflect.TypeOf((*MockInterface)(nil).GetLastDateWithMonthlyInfo))
 }
 
+// GetMissingViews mocks base method.
+func (m *MockInterface) GetMissingViews(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMissingViews", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMissingViews indicates an expected call of GetMissingViews.
+func (mr *MockInterfaceMockRecorder) GetMissingViews(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMissingViews", reflect.TypeOf((*MockInterface)(nil).GetMissingViews), ctx)
+}
+
 // GetMonthlyInfo mocks base method.
-func (m *MockInterface) GetMonthlyInfo(agencies []models.Agency, year int) (map[string][]models.AgencyMonthlyInfo, error) {
+func (m *MockInterface) GetMonthlyInfo(agencies []models.Agency, year int, months ...models.MonthRange) (map[string][]models.AgencyMonthlyInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMonthlyInfo", agencies, year)
+	varargs := []interface{}{agencies, year}
+	for _, a := range months {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetMonthlyInfo", varargs...)
 	ret0, _ := ret[0].(map[string][]models.AgencyMonthlyInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMonthlyInfo indicates an expected call of GetMonthlyInfo.
-func (mr *MockInterfaceMockRecorder) GetMonthlyInfo(agencies, year interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) GetMonthlyInfo(agencies, year interface{}, months ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlyInfo", reflect.TypeOf((*MockInterface)(nil).GetMonthlyInfo), agencies, year)
+	varargs := append([]interface{}{agencies, year}, months...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlyInfo", reflect.TypeOf((*MockInterface)(nil).GetMonthlyInfo), varargs...)
+}
+
+// GetMonthlyInfoInPeriod mocks base method.
+func (m *MockInterface) GetMonthlyInfoInPeriod(agencies []models.Agency, period models.Period) (map[string][]models.AgencyMonthlyInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonthlyInfoInPeriod", agencies, period)
+	ret0, _ := ret[0].(map[string][]models.AgencyMonthlyInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonthlyInfoInPeriod indicates an expected call of GetMonthlyInfoInPeriod.
+func (mr *MockInterfaceMockRecorder) GetMonthlyInfoInPeriod(agencies, period interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlyInfoInPeriod", reflect.TypeOf((*MockInterface)(nil).GetMonthlyInfoInPeriod), agencies, period)
 }
 
 // GetNotices mocks base method.
@@ -350,6 +550,36 @@ func (mr *MockInterfaceMockRecorder) GetOPJ(group interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOPJ", reflect.TypeOf((*MockInterface)(nil).GetOPJ), group)
 }
 
+// GetOutlierPaychecks mocks base method.
+func (m *MockInterface) GetOutlierPaychecks(opts models.OutlierOpts) ([]models.OutlierPaycheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutlierPaychecks", opts)
+	ret0, _ := ret[0].([]models.OutlierPaycheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutlierPaychecks indicates an expected call of GetOutlierPaychecks.
+func (mr *MockInterfaceMockRecorder) GetOutlierPaychecks(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutlierPaychecks", reflect.TypeOf((*MockInterface)(nil).GetOutlierPaychecks), opts)
+}
+
+// GetPackages mocks base method.
+func (m *MockInterface) GetPackages(opts models.PackageFilterOpts) ([]models.Package, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPackages", opts)
+	ret0, _ := ret[0].([]models.Package)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPackages indicates an expected call of GetPackages.
+func (mr *MockInterfaceMockRecorder) GetPackages(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPackages", reflect.TypeOf((*MockInterface)(nil).GetPackages), opts)
+}
+
 // GetPaycheckItems mocks base method.
 func (m *MockInterface) GetPaycheckItems(agency models.Agency, year int) ([]models.PaycheckItem, error) {
 	m.ctrl.T.Helper()
@@ -365,6 +595,21 @@ func (mr *MockInterfaceMockRecorder) GetPaycheckItems(agency, year interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaycheckItems", reflect.TypeOf((*MockInterface)(nil).GetPaycheckItems), agency, year)
 }
 
+// GetPaycheckItemsInPeriod mocks base method.
+func (m *MockInterface) GetPaycheckItemsInPeriod(agency models.Agency, period models.Period) ([]models.PaycheckItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaycheckItemsInPeriod", agency, period)
+	ret0, _ := ret[0].([]models.PaycheckItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaycheckItemsInPeriod indicates an expected call of GetPaycheckItemsInPeriod.
+func (mr *MockInterfaceMockRecorder) GetPaycheckItemsInPeriod(agency, period interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaycheckItemsInPeriod", reflect.TypeOf((*MockInterface)(nil).GetPaycheckItemsInPeriod), agency, period)
+}
+
 // GetPaychecks mocks base method.
 func (m *MockInterface) GetPaychecks(agency models.Agency, year int) ([]models.Paycheck, error) {
 	m.ctrl.T.Helper()
@@ -380,6 +625,106 @@ func (mr *MockInterfaceMockRecorder) GetPaychecks(agency, year interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaychecks", reflect.TypeOf((*MockInterface)(nil).GetPaychecks), agency, year)
 }
 
+// GetPaychecksInPeriod mocks base method.
+func (m *MockInterface) GetPaychecksInPeriod(agency models.Agency, period models.Period) ([]models.Paycheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaychecksInPeriod", agency, period)
+	ret0, _ := ret[0].([]models.Paycheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaychecksInPeriod indicates an expected call of GetPaychecksInPeriod.
+func (mr *MockInterfaceMockRecorder) GetPaychecksInPeriod(agency, period interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaychecksInPeriod", reflect.TypeOf((*MockInterface)(nil).GetPaychecksInPeriod), agency, period)
+}
+
+// GetPriceIndex mocks base method.
+func (m *MockInterface) GetPriceIndex() ([]models.PriceIndex, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceIndex")
+	ret0, _ := ret[0].([]models.PriceIndex)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceIndex indicates an expected call of GetPriceIndex.
+func (mr *MockInterfaceMockRecorder) GetPriceIndex() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceIndex", reflect.TypeOf((*MockInterface)(nil).GetPriceIndex))
+}
+
+// GetRanking mocks base method.
+func (m *MockInterface) GetRanking(opts models.RankingOpts) ([]models.RankingEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRanking", opts)
+	ret0, _ := ret[0].([]models.RankingEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRanking indicates an expected call of GetRanking.
+func (mr *MockInterfaceMockRecorder) GetRanking(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRanking", reflect.TypeOf((*MockInterface)(nil).GetRanking), opts)
+}
+
+// GetRemunerationCaps mocks base method.
+func (m *MockInterface) GetRemunerationCaps() ([]models.RemunerationCap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRemunerationCaps")
+	ret0, _ := ret[0].([]models.RemunerationCap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemunerationCaps indicates an expected call of GetRemunerationCaps.
+func (mr *MockInterfaceMockRecorder) GetRemunerationCaps() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemunerationCaps", reflect.TypeOf((*MockInterface)(nil).GetRemunerationCaps))
+}
+
+// GetRemunerationsByRole mocks base method.
+func (m *MockInterface) GetRemunerationsByRole(agencyID string, period models.Period, opts ...models.AggregationOpts) ([]models.RemunerationGroup, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{agencyID, period}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetRemunerationsByRole", varargs...)
+	ret0, _ := ret[0].([]models.RemunerationGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemunerationsByRole indicates an expected call of GetRemunerationsByRole.
+func (mr *MockInterfaceMockRecorder) GetRemunerationsByRole(agencyID, period interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{agencyID, period}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemunerationsByRole", reflect.TypeOf((*MockInterface)(nil).GetRemunerationsByRole), varargs...)
+}
+
+// GetRemunerationsByWorkplace mocks base method.
+func (m *MockInterface) GetRemunerationsByWorkplace(agencyID string, period models.Period, opts ...models.AggregationOpts) ([]models.RemunerationGroup, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{agencyID, period}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetRemunerationsByWorkplace", varargs...)
+	ret0, _ := ret[0].([]models.RemunerationGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemunerationsByWorkplace indicates an expected call of GetRemunerationsByWorkplace.
+func (mr *MockInterfaceMockRecorder) GetRemunerationsByWorkplace(agencyID, period interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{agencyID, period}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemunerationsByWorkplace", reflect.TypeOf((*MockInterface)(nil).GetRemunerationsByWorkplace), varargs...)
+}
+
 // GetRetroactivePayments mocks base method.
 func (m *MockInterface) GetRetroactivePayments(agency models.Agency, year, month int) ([]models.RetroactivePayments, error) {
 	m.ctrl.T.Helper()
@@ -395,6 +740,36 @@ func (mr *MockInterfaceMockRecorder) GetRetroactivePayments(agency, year, month
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRetroactivePayments", reflect.TypeOf((*MockInterface)(nil).GetRetroactivePayments), agency, year, month)
 }
 
+// GetRoleNormalizations mocks base method.
+func (m *MockInterface) GetRoleNormalizations() ([]models.RoleNormalization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoleNormalizations")
+	ret0, _ := ret[0].([]models.RoleNormalization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleNormalizations indicates an expected call of GetRoleNormalizations.
+func (mr *MockInterfaceMockRecorder) GetRoleNormalizations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleNormalizations", reflect.TypeOf((*MockInterface)(nil).GetRoleNormalizations))
+}
+
+// GetSchemaVersion mocks base method.
+func (m *MockInterface) GetSchemaVersion(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchemaVersion", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchemaVersion indicates an expected call of GetSchemaVersion.
+func (mr *MockInterfaceMockRecorder) GetSchemaVersion(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchemaVersion", reflect.TypeOf((*MockInterface)(nil).GetSchemaVersion), ctx)
+}
+
 // GetStateAgencies mocks base method.
 func (m *MockInterface) GetStateAgencies(uf string) ([]models.Agency, error) {
 	m.ctrl.T.Helper()
@@ -410,20 +785,92 @@ func (mr *MockInterfaceMockRecorder) GetStateAgencies(uf interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStateAgencies", reflect.TypeOf((*MockInterface)(nil).GetStateAgencies), uf)
 }
 
-// Store mocks base method.
-func (m *MockInterface) Store(agmi models.AgencyMonthlyInfo) error {
+// Ping mocks base method.
+func (m *MockInterface) Ping(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Store", agmi)
+	ret := m.ctrl.Call(m, "Ping", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// Ping indicates an expected call of Ping.
+func (mr *MockInterfaceMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockInterface)(nil).Ping), ctx)
+}
+
+// ReplacePaychecks mocks base method.
+func (m *MockInterface) ReplacePaychecks(p []models.Paycheck, r []models.PaycheckItem) (*models.PaycheckStoreReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplacePaychecks", p, r)
+	ret0, _ := ret[0].(*models.PaycheckStoreReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplacePaychecks indicates an expected call of ReplacePaychecks.
+func (mr *MockInterfaceMockRecorder) ReplacePaychecks(p, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplacePaychecks", reflect.TypeOf((*MockInterface)(nil).ReplacePaychecks), p, r)
+}
+
+// Store mocks base method.
+func (m *MockInterface) Store(agmi models.AgencyMonthlyInfo) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Store", agmi)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // Store indicates an expected call of Store.
 func (mr *MockInterfaceMockRecorder) Store(agmi interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockInterface)(nil).Store), agmi)
 }
 
+// StoreAnomalies mocks base method.
+func (m *MockInterface) StoreAnomalies(agencyID string, period models.Period, anomalies []models.Anomaly) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreAnomalies", agencyID, period, anomalies)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreAnomalies indicates an expected call of StoreAnomalies.
+func (mr *MockInterfaceMockRecorder) StoreAnomalies(agencyID, period, anomalies interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreAnomalies", reflect.TypeOf((*MockInterface)(nil).StoreAnomalies), agencyID, period, anomalies)
+}
+
+// StoreCollection mocks base method.
+func (m *MockInterface) StoreCollection(col models.Collection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreCollection", col)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreCollection indicates an expected call of StoreCollection.
+func (mr *MockInterfaceMockRecorder) StoreCollection(col interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreCollection", reflect.TypeOf((*MockInterface)(nil).StoreCollection), col)
+}
+
+// StorePackage mocks base method.
+func (m *MockInterface) StorePackage(pkg models.Package) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorePackage", pkg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StorePackage indicates an expected call of StorePackage.
+func (mr *MockInterfaceMockRecorder) StorePackage(pkg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorePackage", reflect.TypeOf((*MockInterface)(nil).StorePackage), pkg)
+}
+
 // StorePaychecks mocks base method.
 func (m *MockInterface) StorePaychecks(p []models.Paycheck, r []models.PaycheckItem) error {
 	m.ctrl.T.Helper()
@@ -438,6 +885,34 @@ func (mr *MockInterfaceMockRecorder) StorePaychecks(p, r interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorePaychecks", reflect.TypeOf((*MockInterface)(nil).StorePaychecks), p, r)
 }
 
+// StorePriceIndex mocks base method.
+func (m *MockInterface) StorePriceIndex(series []models.PriceIndex) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorePriceIndex", series)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StorePriceIndex indicates an expected call of StorePriceIndex.
+func (mr *MockInterfaceMockRecorder) StorePriceIndex(series interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorePriceIndex", reflect.TypeOf((*MockInterface)(nil).StorePriceIndex), series)
+}
+
+// StoreRemunerationCaps mocks base method.
+func (m *MockInterface) StoreRemunerationCaps(caps []models.RemunerationCap) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreRemunerationCaps", caps)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreRemunerationCaps indicates an expected call of StoreRemunerationCaps.
+func (mr *MockInterfaceMockRecorder) StoreRemunerationCaps(caps interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreRemunerationCaps", reflect.TypeOf((*MockInterface)(nil).StoreRemunerationCaps), caps)
+}
+
 // StoreRemunerations mocks base method.
 func (m *MockInterface) StoreRemunerations(remu models.Remunerations) error {
 	m.ctrl.T.Helper()
@@ -451,3 +926,17 @@ func (mr *MockInterfaceMockRecorder) StoreRemunerations(remu interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreRemunerations", reflect.TypeOf((*MockInterface)(nil).StoreRemunerations), remu)
 }
+
+// StoreRoleNormalizations mocks base method.
+func (m *MockInterface) StoreRoleNormalizations(rules []models.RoleNormalization) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreRoleNormalizations", rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreRoleNormalizations indicates an expected call of StoreRoleNormalizations.
+func (mr *MockInterfaceMockRecorder) StoreRoleNormalizations(rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreRoleNormalizations", reflect.TypeOf((*MockInterface)(nil).StoreRoleNormalizations), rules)
+}
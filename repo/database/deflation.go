@@ -0,0 +1,124 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dadosjusbr/storage/models"
+	"github.com/dadosjusbr/storage/repo/database/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StorePriceIndex armazena (ou atualiza) os valores da série do índice de preços.
+func (p *PostgresDB) StorePriceIndex(series []models.PriceIndex) error {
+	if len(series) == 0 {
+		return nil
+	}
+	defer p.markWrite()
+	dtoSeries := make([]dto.PriceIndexDTO, 0, len(series))
+	for _, pi := range series {
+		dtoSeries = append(dtoSeries, *dto.NewPriceIndexDTO(pi))
+	}
+	if err := p.db.Model(dto.PriceIndexDTO{}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ano"}, {Name: "mes"}},
+		UpdateAll: true,
+	}).CreateInBatches(&dtoSeries, 500).Error; err != nil {
+		return fmt.Errorf("error inserting 'indice_precos': %w", classify(err))
+	}
+	return nil
+}
+
+// GetPriceIndex retorna a série do índice de preços, ordenada por ano e mês.
+func (p *PostgresDB) GetPriceIndex() ([]models.PriceIndex, error) {
+	var dtoSeries []dto.PriceIndexDTO
+	if err := p.reader().Model(&dto.PriceIndexDTO{}).Order("ano, mes").Find(&dtoSeries).Error; err != nil {
+		return nil, fmt.Errorf("error getting price index: %w", classify(err))
+	}
+	var series []models.PriceIndex
+	for _, pi := range dtoSeries {
+		series = append(series, *pi.ConvertToModel())
+	}
+	return series, nil
+}
+
+// deflation converte valores nominais para preços de um mês de referência, usando a série
+// armazenada em 'indice_precos'. Uma deflação nil não altera os valores.
+type deflation struct {
+	ref float64 // Valor do índice no mês de referência
+}
+
+// newDeflation retorna a deflação pedida nas opções, ou nil se não houver.
+func (p *PostgresDB) newDeflation(opts []models.AggregationOpts) (*deflation, error) {
+	if len(opts) == 0 || opts[0].DeflateTo == nil {
+		return nil, nil
+	}
+	to := opts[0].DeflateTo
+	if to.Month < 1 || to.Month > 12 {
+		return nil, models.NewError(models.ErrInvalidInput, fmt.Errorf("invalid reference month: %d", to.Month))
+	}
+	var ref dto.PriceIndexDTO
+	if err := p.reader().Model(&dto.PriceIndexDTO{}).Where("ano = ? AND mes = ?", to.Year, to.Month).First(&ref).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.NewError(models.ErrNotFound, fmt.Errorf("price index not available for the reference month %02d/%d", to.Month, to.Year))
+		}
+		return nil, fmt.Errorf("error getting reference price index: %w", classify(err))
+	}
+	return &deflation{ref: ref.Value}, nil
+}
+
+// factor retorna a expressão SQL do fator que converte os valores das linhas da tabela
+// (que deve ter as colunas ano e mes) para preços do mês de referência.
+func (d *deflation) factor(table string) string {
+	if d == nil {
+		return "1"
+	}
+	return fmt.Sprintf("(SELECT %s / ip.valor FROM indice_precos ip WHERE ip.ano = %[2]s.ano AND ip.mes = %[2]s.mes)",
+		strconv.FormatFloat(d.ref, 'f', -1, 64), table)
+}
+
+// check verifica se a série do índice de preços cobre todos os meses das linhas selecionadas
+// pela consulta (sobre a tabela, que deve ter as colunas ano e mes). Sem essa verificação, os
+// valores dos meses sem índice seriam ignorados silenciosamente nas somas e médias.
+func (d *deflation) check(query *gorm.DB, table string) error {
+	if d == nil {
+		return nil
+	}
+	var missing []dto.PriceIndexDTO
+	m := query.Select(fmt.Sprintf("%[1]s.ano, %[1]s.mes", table))
+	m = m.Where(fmt.Sprintf("NOT EXISTS (SELECT 1 FROM indice_precos ip WHERE ip.ano = %[1]s.ano AND ip.mes = %[1]s.mes)", table))
+	if err := m.Limit(1).Scan(&missing).Error; err != nil {
+		return fmt.Errorf("error checking price index coverage: %w", classify(err))
+	}
+	if len(missing) > 0 {
+		return models.NewError(models.ErrNotFound, fmt.Errorf("price index not available for %02d/%d", missing[0].Month, missing[0].Year))
+	}
+	return nil
+}
+
+// perMemberAverages é a consulta equivalente à view 'media_por_membro', com os valores
+// convertidos pela deflação. Os contracheques são filtrados pela condição where.
+func (d *deflation) perMemberAverages(where string) string {
+	f := d.factor("c")
+	return fmt.Sprintf(`SELECT m.orgao, m.ano,
+			avg(m.salario) AS salario,
+			avg(m.beneficios) AS beneficios,
+			avg(m.descontos) AS descontos,
+			avg(m.remuneracao) AS remuneracao,
+			percentile_cont(%[3]s) WITHIN GROUP (ORDER BY m.salario) AS salario_percentis,
+			percentile_cont(%[3]s) WITHIN GROUP (ORDER BY m.beneficios) AS beneficios_percentis,
+			percentile_cont(%[3]s) WITHIN GROUP (ORDER BY m.descontos) AS descontos_percentis,
+			percentile_cont(%[3]s) WITHIN GROUP (ORDER BY m.remuneracao) AS remuneracao_percentis
+		FROM (SELECT c.orgao, c.ano, c.nome_sanitizado,
+				count(*) AS num_meses,
+				avg(c.salario * %[1]s) AS salario,
+				avg(c.beneficios * %[1]s) AS beneficios,
+				avg(c.descontos * %[1]s) AS descontos,
+				avg(c.remuneracao * %[1]s) AS remuneracao
+			FROM contracheques c
+			WHERE %[2]s
+			GROUP BY c.orgao, c.ano, c.nome_sanitizado) m
+		WHERE m.num_meses > 1
+		GROUP BY m.orgao, m.ano`, f, where, dto.PercentileFractions)
+}
@@ -28,11 +28,11 @@ func (a AgencyDTO) ConvertToModel() (*models.Agency, error) {
 	var collecting []models.Collecting
 	collectingBytes, err := a.Collecting.MarshalJSON()
 	if err != nil {
-		return nil, fmt.Errorf("error while marshaling collecting: %q", err)
+		return nil, fmt.Errorf("error while marshaling collecting: %w", err)
 	}
 	err = json.Unmarshal(collectingBytes, &collecting)
 	if err != nil {
-		return nil, fmt.Errorf("error while unmarshaling collecting: %q", err)
+		return nil, fmt.Errorf("error while unmarshaling collecting: %w", err)
 	}
 	return &models.Agency{
 		ID:            a.ID,
@@ -49,7 +49,7 @@ func (a AgencyDTO) ConvertToModel() (*models.Agency, error) {
 func NewAgencyDTO(agency models.Agency) (*AgencyDTO, error) {
 	collecting, err := json.Marshal(agency.Collecting)
 	if err != nil {
-		return nil, fmt.Errorf("error while marshaling collecting: %q", err)
+		return nil, fmt.Errorf("error while marshaling collecting: %w", err)
 	}
 	return &AgencyDTO{
 		ID:            agency.ID,
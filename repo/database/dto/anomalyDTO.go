@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/dadosjusbr/storage/models"
+)
+
+// AnomalyDTO é um mês de um órgão com valor atípico de uma métrica.
+type AnomalyDTO struct {
+	AgencyID   string    `gorm:"column:id_orgao"`
+	Month      int       `gorm:"column:mes"`
+	Year       int       `gorm:"column:ano"`
+	Metric     string    `gorm:"column:metrica"`
+	Item       string    `gorm:"column:rubrica"`
+	Value      float64   `gorm:"column:valor"`
+	Median     float64   `gorm:"column:mediana"`
+	Score      float64   `gorm:"column:escore"`
+	DetectedAt time.Time `gorm:"column:detectado_em"`
+}
+
+func (AnomalyDTO) TableName() string {
+	return "anomalias"
+}
+
+func NewAnomalyDTO(a models.Anomaly) *AnomalyDTO {
+	detectedAt := a.DetectedAt
+	if detectedAt.IsZero() {
+		detectedAt = time.Now().UTC()
+	}
+	return &AnomalyDTO{
+		AgencyID:   a.AgencyID,
+		Month:      a.Month,
+		Year:       a.Year,
+		Metric:     string(a.Metric),
+		Item:       a.Item,
+		Value:      a.Value,
+		Median:     a.Median,
+		Score:      a.Score,
+		DetectedAt: detectedAt,
+	}
+}
+
+func (a AnomalyDTO) ConvertToModel() *models.Anomaly {
+	return &models.Anomaly{
+		AgencyID:   a.AgencyID,
+		Month:      a.Month,
+		Year:       a.Year,
+		Metric:     models.AnomalyMetric(a.Metric),
+		Item:       a.Item,
+		Value:      a.Value,
+		Median:     a.Median,
+		Score:      a.Score,
+		DetectedAt: a.DetectedAt.UTC(),
+	}
+}
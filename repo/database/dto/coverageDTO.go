@@ -0,0 +1,10 @@
+package dto
+
+// CoverageMonthDTO é um mês de um órgão presente em uma das origens da cobertura (coletas,
+// contracheques, zips de remunerações ou remunerações inconsistentes).
+type CoverageMonthDTO struct {
+	AgencyID string `gorm:"column:orgao"`
+	Year     int    `gorm:"column:ano"`
+	Month    int    `gorm:"column:mes"`
+	Source   string `gorm:"column:origem"`
+}
@@ -5,6 +5,7 @@ import (
 )
 
 type GeneralMonthlyInfoDTO struct {
+	Year               int                `gorm:"column:ano"`
 	Month              int                `gorm:"column:mes"`
 	Count              int                `gorm:"column:num_membros"`
 	BaseRemuneration   float64            `gorm:"column:remuneracao_base"`
@@ -27,6 +28,7 @@ type ItemSummary struct {
 
 func NewGeneralMonthlyInfoDTO(gmi models.GeneralMonthlyInfo) *GeneralMonthlyInfoDTO {
 	return &GeneralMonthlyInfoDTO{
+		Year:               gmi.Year,
 		Month:              gmi.Month,
 		Count:              gmi.Count,
 		BaseRemuneration:   gmi.BaseRemuneration,
@@ -39,6 +41,7 @@ func NewGeneralMonthlyInfoDTO(gmi models.GeneralMonthlyInfo) *GeneralMonthlyInfo
 
 func (gmi *GeneralMonthlyInfoDTO) ConvertToModel() *models.GeneralMonthlyInfo {
 	return &models.GeneralMonthlyInfo{
+		Year:               gmi.Year,
 		Month:              gmi.Month,
 		Count:              gmi.Count,
 		BaseRemuneration:   gmi.BaseRemuneration,
@@ -0,0 +1,40 @@
+package dto
+
+import "github.com/dadosjusbr/storage/models"
+
+// GroupedMonthlyInfoDTO são os totais de um mês para um grupo de órgãos.
+type GroupedMonthlyInfoDTO struct {
+	Group              string  `gorm:"column:grupo"`
+	Year               int     `gorm:"column:ano"`
+	Month              int     `gorm:"column:mes"`
+	NumAgencies        int     `gorm:"column:num_orgaos"`
+	Count              int     `gorm:"column:num_membros"`
+	BaseRemuneration   float64 `gorm:"column:remuneracao_base"`
+	OtherRemunerations float64 `gorm:"column:outras_remuneracoes"`
+	Discounts          float64 `gorm:"column:descontos"`
+	Remunerations      float64 `gorm:"column:remuneracoes"`
+}
+
+// GroupedMonthlyItemTotalDTO é o total de uma rubrica em um mês para um grupo de órgãos.
+type GroupedMonthlyItemTotalDTO struct {
+	Group string  `gorm:"column:grupo"`
+	Year  int     `gorm:"column:ano"`
+	Month int     `gorm:"column:mes"`
+	Item  string  `gorm:"column:rubrica"`
+	Value float64 `gorm:"column:valor"`
+}
+
+func (g GroupedMonthlyInfoDTO) ConvertToModel(items models.ItemSummary) *models.GroupedMonthlyInfo {
+	return &models.GroupedMonthlyInfo{
+		Group:              g.Group,
+		Year:               g.Year,
+		Month:              g.Month,
+		NumAgencies:        g.NumAgencies,
+		Count:              g.Count,
+		BaseRemuneration:   g.BaseRemuneration,
+		OtherRemunerations: g.OtherRemunerations,
+		Discounts:          g.Discounts,
+		Remunerations:      g.Remunerations,
+		ItemSummary:        items,
+	}
+}
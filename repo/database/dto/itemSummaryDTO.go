@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"sort"
+
+	"github.com/dadosjusbr/storage/models"
+)
+
+// ItemSummaryDTO é o valor total de uma rubrica na coleta atual de um órgão/mês/ano.
+type ItemSummaryDTO struct {
+	AgencyID string  `gorm:"column:id_orgao"`
+	Month    int     `gorm:"column:mes"`
+	Year     int     `gorm:"column:ano"`
+	Item     string  `gorm:"column:rubrica"`
+	Value    float64 `gorm:"column:valor"`
+}
+
+func (ItemSummaryDTO) TableName() string {
+	return "resumo_rubricas"
+}
+
+// NewItemSummaryDTOs cria uma linha de 'resumo_rubricas' para cada rubrica do sumário da coleta.
+func NewItemSummaryDTOs(agmi models.AgencyMonthlyInfo) []ItemSummaryDTO {
+	if agmi.Summary == nil {
+		return nil
+	}
+	var items []ItemSummaryDTO
+	for item, value := range agmi.Summary.ItemSummary {
+		items = append(items, ItemSummaryDTO{
+			AgencyID: agmi.AgencyID,
+			Month:    agmi.Month,
+			Year:     agmi.Year,
+			Item:     item,
+			Value:    value,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Item < items[j].Item })
+	return items
+}
+
+// GroupedItemTotalDTO é o valor total de uma rubrica em um grupo (ano ou mês).
+type GroupedItemTotalDTO struct {
+	Group int     `gorm:"column:grupo"`
+	Item  string  `gorm:"column:rubrica"`
+	Value float64 `gorm:"column:valor"`
+}
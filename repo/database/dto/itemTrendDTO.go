@@ -0,0 +1,12 @@
+package dto
+
+// ItemTrendDTO são os totais de uma rubrica (nome sanitizado e categoria), no período ou em um mês.
+type ItemTrendDTO struct {
+	Item          string  `gorm:"column:rubrica"`
+	Category      string  `gorm:"column:categoria"`
+	Year          int     `gorm:"column:ano"`
+	Month         int     `gorm:"column:mes"`
+	Total         float64 `gorm:"column:total"`
+	NumRecipients int     `gorm:"column:num_beneficiarios"`
+	NumAgencies   int     `gorm:"column:num_orgaos"`
+}
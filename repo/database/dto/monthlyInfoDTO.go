@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -31,8 +33,10 @@ type AgencyMonthlyInfoDTO struct {
 	Duration       float64        `gorm:"column:duracao_segundos"` // Tempo de execução da coleta em segundos
 	Meta
 	Score
-	ManualCollection bool `gorm:"column:manual"` // A coleta foi realizada manualmente?
-	Inconsistent     bool `gorm:"column:inconsistente;<-:false"`
+	ManualCollection bool       `gorm:"column:manual"` // A coleta foi realizada manualmente?
+	Inconsistent     bool       `gorm:"column:inconsistente;<-:false"`
+	Fingerprint      string     `gorm:"column:impressao_digital"` // Hash do conteúdo da coleta (ver Fingerprint)
+	VerifiedAt       *time.Time `gorm:"column:verificado_em"`     // Última vez que uma coleta idêntica foi armazenada
 }
 
 func (AgencyMonthlyInfoDTO) TableName() string {
@@ -67,38 +71,43 @@ func (a AgencyMonthlyInfoDTO) ConvertToModel() (*models.AgencyMonthlyInfo, error
 
 	backupBytes, err := a.Backup.MarshalJSON()
 	if err != nil {
-		return nil, fmt.Errorf("error while marshaling backup: %q", err)
+		return nil, fmt.Errorf("error while marshaling backup: %w", err)
 	}
 	err = json.Unmarshal(backupBytes, &backup)
 	if err != nil {
-		return nil, fmt.Errorf("error while unmarshaling backup: %q", err)
+		return nil, fmt.Errorf("error while unmarshaling backup: %w", err)
 	}
 
 	summaryBytes, err := a.Summary.MarshalJSON()
 	if err != nil {
-		return nil, fmt.Errorf("error while marshaling summary: %q", err)
+		return nil, fmt.Errorf("error while marshaling summary: %w", err)
 	}
 	err = json.Unmarshal(summaryBytes, &summary)
 	if err != nil {
-		return nil, fmt.Errorf("error while unmarshaling summary: %q", err)
+		return nil, fmt.Errorf("error while unmarshaling summary: %w", err)
 	}
 
 	procInfoBytes, err := a.ProcInfo.MarshalJSON()
 	if err != nil {
-		return nil, fmt.Errorf("error while marshaling procInfo: %q", err)
+		return nil, fmt.Errorf("error while marshaling procInfo: %w", err)
 	}
 	err = json.Unmarshal(procInfoBytes, &procInfo)
 	if err != nil {
-		return nil, fmt.Errorf("error while unmarshaling procInfo: %q", err)
+		return nil, fmt.Errorf("error while unmarshaling procInfo: %w", err)
 	}
 
 	pkgBytes, err := a.Package.MarshalJSON()
 	if err != nil {
-		return nil, fmt.Errorf("error while marshaling package: %q", err)
+		return nil, fmt.Errorf("error while marshaling package: %w", err)
 	}
 	err = json.Unmarshal(pkgBytes, &pkg)
 	if err != nil {
-		return nil, fmt.Errorf("error while unmarshaling package: %q", err)
+		return nil, fmt.Errorf("error while unmarshaling package: %w", err)
+	}
+
+	var lastVerified *timestamppb.Timestamp
+	if a.VerifiedAt != nil {
+		lastVerified = timestamppb.New(*a.VerifiedAt)
 	}
 
 	return &models.AgencyMonthlyInfo{
@@ -135,6 +144,7 @@ func (a AgencyMonthlyInfoDTO) ConvertToModel() (*models.AgencyMonthlyInfo, error
 		Duration:         a.Duration,
 		ManualCollection: a.ManualCollection,
 		Inconsistent:     a.Inconsistent,
+		LastVerified:     lastVerified,
 	}, nil
 }
 
@@ -145,19 +155,19 @@ func NewAgencyMonthlyInfoDTO(agmi models.AgencyMonthlyInfo) (*AgencyMonthlyInfoD
 	}
 	backup, err := json.Marshal(bkp)
 	if err != nil {
-		return nil, fmt.Errorf("error while marshaling backup: %q", err)
+		return nil, fmt.Errorf("error while marshaling backup: %w", err)
 	}
 	summary, err := json.Marshal(agmi.Summary)
 	if err != nil {
-		return nil, fmt.Errorf("error while marshaling summary: %q", err)
+		return nil, fmt.Errorf("error while marshaling summary: %w", err)
 	}
 	procInfo, err := json.Marshal(agmi.ProcInfo)
 	if err != nil {
-		return nil, fmt.Errorf("error while marshaling procInfo: %q", err)
+		return nil, fmt.Errorf("error while marshaling procInfo: %w", err)
 	}
 	pkg, err := json.Marshal(agmi.Package)
 	if err != nil {
-		return nil, fmt.Errorf("error while marshaling package: %q", err)
+		return nil, fmt.Errorf("error while marshaling package: %w", err)
 	}
 
 	var score Score
@@ -194,6 +204,10 @@ func NewAgencyMonthlyInfoDTO(agmi models.AgencyMonthlyInfo) (*AgencyMonthlyInfoD
 	} else {
 		timestamp = time.Now()
 	}
+	fingerprint, err := Fingerprint(agmi)
+	if err != nil {
+		return nil, err
+	}
 
 	return &AgencyMonthlyInfoDTO{
 		ID:               fmt.Sprintf("%s/%s/%d", agmi.AgencyID, AddZeroes(agmi.Month), agmi.Year),
@@ -215,9 +229,52 @@ func NewAgencyMonthlyInfoDTO(agmi models.AgencyMonthlyInfo) (*AgencyMonthlyInfoD
 		Duration:         agmi.Duration,
 		ManualCollection: agmi.ManualCollection,
 		Inconsistent:     agmi.Inconsistent,
+		Fingerprint:      fingerprint,
+		VerifiedAt:       &timestamp,
 	}, nil
 }
 
+// Fingerprint calcula a impressão digital do conteúdo de uma coleta: sumário, hashes do
+// backup e do pacote, metadados, índices, repositórios e versões do coletor e do parser e se
+// a coleta foi manual. Coletas com a mesma impressão digital são consideradas idênticas. O
+// momento e a duração da coleta mudam a cada execução e por isso não fazem parte dela; o
+// momento de uma coleta idêntica é guardado em 'verificado_em'.
+func Fingerprint(agmi models.AgencyMonthlyInfo) (string, error) {
+	content := struct {
+		Summary          *models.Summary
+		BackupHash       string
+		PackageHash      string
+		Meta             *models.Meta
+		Score            *models.Score
+		CrawlerRepo      string
+		CrawlerVersion   string
+		ParserRepo       string
+		ParserVersion    string
+		ManualCollection bool
+	}{
+		Summary:          agmi.Summary,
+		Meta:             agmi.Meta,
+		Score:            agmi.Score,
+		CrawlerRepo:      agmi.CrawlerRepo,
+		CrawlerVersion:   agmi.CrawlerVersion,
+		ParserRepo:       agmi.ParserRepo,
+		ParserVersion:    agmi.ParserVersion,
+		ManualCollection: agmi.ManualCollection,
+	}
+	if len(agmi.Backups) > 0 {
+		content.BackupHash = agmi.Backups[0].Hash
+	}
+	if agmi.Package != nil {
+		content.PackageHash = agmi.Package.Hash
+	}
+	b, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("error while marshaling collection content: %w", err)
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
+
 // Funcão que adiciona um zero a esquerda a um número caso ele seja menor que 10
 func AddZeroes(num int) string {
 	numStr := strconv.Itoa(num)
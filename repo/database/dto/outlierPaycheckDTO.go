@@ -0,0 +1,44 @@
+package dto
+
+import "github.com/dadosjusbr/storage/models"
+
+// OutlierPaycheckDTO é um contracheque com valor muito acima dos contracheques de mesma função no mês.
+type OutlierPaycheckDTO struct {
+	PaycheckID int     `gorm:"column:id"`
+	AgencyID   string  `gorm:"column:orgao"`
+	Month      int     `gorm:"column:mes"`
+	Year       int     `gorm:"column:ano"`
+	Name       string  `gorm:"column:nome"`
+	Role       string  `gorm:"column:funcao"`
+	Reason     string  `gorm:"column:motivo"`
+	Item       string  `gorm:"column:rubrica"`
+	Value      float64 `gorm:"column:valor"`
+	Score      float64 `gorm:"column:escore"`
+	NumPeers   int     `gorm:"column:num_pares"`
+	Median     float64 `gorm:"column:mediana"`
+	MAD        float64 `gorm:"column:mad"`
+	MeanAbsDev float64 `gorm:"column:desvio_medio"`
+	Scale      float64 `gorm:"column:escala"`
+}
+
+func (o OutlierPaycheckDTO) ConvertToModel() *models.OutlierPaycheck {
+	return &models.OutlierPaycheck{
+		PaycheckID: o.PaycheckID,
+		AgencyID:   o.AgencyID,
+		Month:      o.Month,
+		Year:       o.Year,
+		Name:       o.Name,
+		Role:       o.Role,
+		Reason:     models.OutlierReason(o.Reason),
+		Item:       o.Item,
+		Value:      o.Value,
+		Score:      o.Score,
+		Peers: models.PeerStats{
+			NumPeers:         o.NumPeers,
+			Median:           o.Median,
+			MAD:              o.MAD,
+			MeanAbsDeviation: o.MeanAbsDev,
+			Scale:            o.Scale,
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dadosjusbr/storage/models"
+	"gorm.io/datatypes"
+)
+
+// PackageDTO representa um datapackage (zip) disponível no file storage.
+type PackageDTO struct {
+	Key      string         `gorm:"column:chave"`
+	Scope    string         `gorm:"column:escopo"`
+	AgencyID *string        `gorm:"column:id_orgao"`
+	Month    *int           `gorm:"column:mes"`
+	Year     *int           `gorm:"column:ano"`
+	Group    *string        `gorm:"column:grupo"`
+	Package  datatypes.JSON `gorm:"column:pacote"`
+}
+
+func (PackageDTO) TableName() string {
+	return "pacotes"
+}
+
+func (p PackageDTO) ConvertToModel() (*models.Package, error) {
+	var pkg models.Backup
+	pkgBytes, err := p.Package.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("error while marshaling package: %w", err)
+	}
+	err = json.Unmarshal(pkgBytes, &pkg)
+	if err != nil {
+		return nil, fmt.Errorf("error while unmarshaling package: %w", err)
+	}
+	return &models.Package{
+		Key:      p.Key,
+		Scope:    models.PackageScope(p.Scope),
+		AgencyID: p.AgencyID,
+		Month:    p.Month,
+		Year:     p.Year,
+		Group:    p.Group,
+		Package:  pkg,
+	}, nil
+}
+
+func NewPackageDTO(p models.Package) (*PackageDTO, error) {
+	pkg, err := json.Marshal(p.Package)
+	if err != nil {
+		return nil, fmt.Errorf("error while marshaling package: %w", err)
+	}
+	return &PackageDTO{
+		Key:      p.Key,
+		Scope:    string(p.InferScope()),
+		AgencyID: p.AgencyID,
+		Month:    p.Month,
+		Year:     p.Year,
+		Group:    p.Group,
+		Package:  pkg,
+	}, nil
+}
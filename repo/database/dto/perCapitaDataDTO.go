@@ -1,6 +1,9 @@
 package dto
 
-import "github.com/dadosjusbr/storage/models"
+import (
+	"github.com/dadosjusbr/storage/models"
+	"github.com/lib/pq"
+)
 
 type PerCapitaData struct {
 	AgencyID           string  `gorm:"column:orgao"`
@@ -9,6 +12,11 @@ type PerCapitaData struct {
 	OtherRemunerations float64 `gorm:"column:beneficios"`
 	Discounts          float64 `gorm:"column:descontos"`
 	Remunerations      float64 `gorm:"column:remuneracao"`
+	// Resultados de percentile_cont(PercentileFractions) sobre as médias dos membros
+	BaseRemunerationPercentiles   pq.Float64Array `gorm:"column:salario_percentis"`
+	OtherRemunerationsPercentiles pq.Float64Array `gorm:"column:beneficios_percentis"`
+	DiscountsPercentiles          pq.Float64Array `gorm:"column:descontos_percentis"`
+	RemunerationsPercentiles      pq.Float64Array `gorm:"column:remuneracao_percentis"`
 }
 
 func (PerCapitaData) TableName() string {
@@ -23,5 +31,10 @@ func (a *PerCapitaData) ConvertToModel() *models.PerCapitaData {
 		OtherRemunerations: a.OtherRemunerations,
 		Discounts:          a.Discounts,
 		Remunerations:      a.Remunerations,
+
+		BaseRemunerationPercentiles:   NewPercentiles(a.BaseRemunerationPercentiles),
+		OtherRemunerationsPercentiles: NewPercentiles(a.OtherRemunerationsPercentiles),
+		DiscountsPercentiles:          NewPercentiles(a.DiscountsPercentiles),
+		RemunerationsPercentiles:      NewPercentiles(a.RemunerationsPercentiles),
 	}
 }
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"github.com/dadosjusbr/storage/models"
+	"github.com/lib/pq"
+)
+
+// PercentileFractions são as frações calculadas com percentile_cont, na ordem esperada por NewPercentiles.
+const PercentileFractions = "ARRAY[0.1, 0.25, 0.5, 0.75, 0.9, 0.99]"
+
+// NewPercentiles converte o resultado de percentile_cont(PercentileFractions) em percentis.
+// Um resultado vazio (sem linhas) resulta em nil, ou seja, percentis não calculados.
+func NewPercentiles(values pq.Float64Array) *models.Percentiles {
+	if len(values) != 6 {
+		return nil
+	}
+	return &models.Percentiles{
+		P10:    values[0],
+		P25:    values[1],
+		Median: values[2],
+		P75:    values[3],
+		P90:    values[4],
+		P99:    values[5],
+	}
+}
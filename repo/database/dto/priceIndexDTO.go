@@ -0,0 +1,30 @@
+package dto
+
+import "github.com/dadosjusbr/storage/models"
+
+// PriceIndexDTO é o valor do índice de preços (ex.: número-índice do IPCA) em um mês/ano.
+type PriceIndexDTO struct {
+	Year  int     `gorm:"column:ano"`
+	Month int     `gorm:"column:mes"`
+	Value float64 `gorm:"column:valor"`
+}
+
+func (PriceIndexDTO) TableName() string {
+	return "indice_precos"
+}
+
+func NewPriceIndexDTO(pi models.PriceIndex) *PriceIndexDTO {
+	return &PriceIndexDTO{
+		Year:  pi.Year,
+		Month: pi.Month,
+		Value: pi.Value,
+	}
+}
+
+func (pi PriceIndexDTO) ConvertToModel() *models.PriceIndex {
+	return &models.PriceIndex{
+		Year:  pi.Year,
+		Month: pi.Month,
+		Value: pi.Value,
+	}
+}
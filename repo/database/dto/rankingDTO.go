@@ -0,0 +1,22 @@
+package dto
+
+import "github.com/dadosjusbr/storage/models"
+
+// RankingEntryDTO é a posição de um órgão em um ranking.
+type RankingEntryDTO struct {
+	Position   int     `gorm:"column:posicao"`
+	AgencyID   string  `gorm:"column:orgao"`
+	Name       string  `gorm:"column:nome"`
+	Value      float64 `gorm:"column:valor"`
+	Percentile float64 `gorm:"column:percentil"`
+}
+
+func (r RankingEntryDTO) ConvertToModel() *models.RankingEntry {
+	return &models.RankingEntry{
+		Position:   r.Position,
+		AgencyID:   r.AgencyID,
+		Name:       r.Name,
+		Value:      r.Value,
+		Percentile: r.Percentile,
+	}
+}
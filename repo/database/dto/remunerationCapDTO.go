@@ -0,0 +1,67 @@
+package dto
+
+import "github.com/dadosjusbr/storage/models"
+
+// RemunerationCapDTO é o teto remuneratório em vigor a partir de um mês/ano.
+type RemunerationCapDTO struct {
+	Year  int     `gorm:"column:ano"`
+	Month int     `gorm:"column:mes"`
+	Value float64 `gorm:"column:valor"`
+}
+
+func (RemunerationCapDTO) TableName() string {
+	return "teto_remuneratorio"
+}
+
+func NewRemunerationCapDTO(c models.RemunerationCap) *RemunerationCapDTO {
+	return &RemunerationCapDTO{
+		Year:  c.Year,
+		Month: c.Month,
+		Value: c.Value,
+	}
+}
+
+func (c RemunerationCapDTO) ConvertToModel() *models.RemunerationCap {
+	return &models.RemunerationCap{
+		Year:  c.Year,
+		Month: c.Month,
+		Value: c.Value,
+	}
+}
+
+// CapExceedanceDTO são os contracheques de um órgão/mês/ano acima do teto.
+type CapExceedanceDTO struct {
+	AgencyID    string  `gorm:"column:orgao"`
+	Month       int     `gorm:"column:mes"`
+	Year        int     `gorm:"column:ano"`
+	Cap         float64 `gorm:"column:teto"`
+	NumMembers  int     `gorm:"column:num_membros"`
+	NumAboveCap int     `gorm:"column:num_acima_teto"`
+	TotalExcess float64 `gorm:"column:excedente_total"`
+}
+
+// AgencyMonthItemTotalDTO é o valor total de uma rubrica em um órgão/mês/ano.
+type AgencyMonthItemTotalDTO struct {
+	AgencyID string  `gorm:"column:orgao"`
+	Month    int     `gorm:"column:mes"`
+	Year     int     `gorm:"column:ano"`
+	Item     string  `gorm:"column:rubrica"`
+	Value    float64 `gorm:"column:valor"`
+}
+
+func (c CapExceedanceDTO) ConvertToModel(items []models.ItemTotal) *models.CapExceedance {
+	ce := &models.CapExceedance{
+		AgencyID:    c.AgencyID,
+		Month:       c.Month,
+		Year:        c.Year,
+		Cap:         c.Cap,
+		NumMembers:  c.NumMembers,
+		NumAboveCap: c.NumAboveCap,
+		TotalExcess: c.TotalExcess,
+		Items:       items,
+	}
+	if c.NumAboveCap > 0 {
+		ce.AverageExcess = c.TotalExcess / float64(c.NumAboveCap)
+	}
+	return ce
+}
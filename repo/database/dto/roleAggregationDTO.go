@@ -0,0 +1,54 @@
+package dto
+
+import "github.com/dadosjusbr/storage/models"
+
+// RoleNormalizationDTO associa as funções que casam com um padrão (ILIKE) a uma função normalizada.
+type RoleNormalizationDTO struct {
+	Pattern string `gorm:"column:padrao"`
+	Role    string `gorm:"column:funcao"`
+}
+
+func (RoleNormalizationDTO) TableName() string {
+	return "funcoes_normalizadas"
+}
+
+func NewRoleNormalizationDTO(r models.RoleNormalization) *RoleNormalizationDTO {
+	return &RoleNormalizationDTO{
+		Pattern: r.Pattern,
+		Role:    r.Role,
+	}
+}
+
+func (r RoleNormalizationDTO) ConvertToModel() *models.RoleNormalization {
+	return &models.RoleNormalization{
+		Pattern: r.Pattern,
+		Role:    r.Role,
+	}
+}
+
+// RemunerationGroupDTO são os totais dos contracheques de um órgão agrupados por função ou lotação.
+type RemunerationGroupDTO struct {
+	Group              string  `gorm:"column:grupo"`
+	NumMembers         int     `gorm:"column:num_membros"`
+	NumPaychecks       int     `gorm:"column:num_contracheques"`
+	BaseRemuneration   float64 `gorm:"column:remuneracao_base"`
+	OtherRemunerations float64 `gorm:"column:outras_remuneracoes"`
+	Discounts          float64 `gorm:"column:descontos"`
+	Remunerations      float64 `gorm:"column:remuneracoes"`
+}
+
+func (r RemunerationGroupDTO) ConvertToModel() *models.RemunerationGroup {
+	g := &models.RemunerationGroup{
+		Group:              r.Group,
+		NumMembers:         r.NumMembers,
+		NumPaychecks:       r.NumPaychecks,
+		BaseRemuneration:   r.BaseRemuneration,
+		OtherRemunerations: r.OtherRemunerations,
+		Discounts:          r.Discounts,
+		Remunerations:      r.Remunerations,
+	}
+	if r.NumPaychecks > 0 {
+		g.AverageRemuneration = r.Remunerations / float64(r.NumPaychecks)
+	}
+	return g
+}
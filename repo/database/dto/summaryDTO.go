@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"github.com/dadosjusbr/storage/models"
+	"github.com/lib/pq"
+)
+
+// SummaryDTO é o resultado das agregações sobre os contracheques de um órgão/mês/ano.
+type SummaryDTO struct {
+	Count             int     `gorm:"column:membros"`
+	BaseMax           float64 `gorm:"column:salario_maximo"`
+	BaseMin           float64 `gorm:"column:salario_minimo"`
+	BaseAverage       float64 `gorm:"column:salario_media"`
+	BaseTotal         float64 `gorm:"column:salario_total"`
+	OtherMax          float64 `gorm:"column:beneficios_maximo"`
+	OtherMin          float64 `gorm:"column:beneficios_minimo"`
+	OtherAverage      float64 `gorm:"column:beneficios_media"`
+	OtherTotal        float64 `gorm:"column:beneficios_total"`
+	DiscountsMax      float64 `gorm:"column:descontos_maximo"`
+	DiscountsMin      float64 `gorm:"column:descontos_minimo"`
+	DiscountsAverage  float64 `gorm:"column:descontos_media"`
+	DiscountsTotal    float64 `gorm:"column:descontos_total"`
+	RemunerationMax   float64 `gorm:"column:remuneracao_maximo"`
+	RemunerationMin   float64 `gorm:"column:remuneracao_minimo"`
+	RemunerationAvg   float64 `gorm:"column:remuneracao_media"`
+	RemunerationTotal float64 `gorm:"column:remuneracao_total"`
+	// Resultados de percentile_cont(PercentileFractions)
+	BasePercentiles         pq.Float64Array `gorm:"column:salario_percentis"`
+	OtherPercentiles        pq.Float64Array `gorm:"column:beneficios_percentis"`
+	DiscountsPercentiles    pq.Float64Array `gorm:"column:descontos_percentis"`
+	RemunerationPercentiles pq.Float64Array `gorm:"column:remuneracao_percentis"`
+}
+
+// HistogramBucketDTO é uma faixa do histograma de renda.
+type HistogramBucketDTO struct {
+	Bucket int `gorm:"column:faixa"`
+	Count  int `gorm:"column:quantidade"`
+}
+
+// ItemTotalDTO é o valor total de uma rubrica.
+type ItemTotalDTO struct {
+	Item  string  `gorm:"column:rubrica"`
+	Value float64 `gorm:"column:valor"`
+}
+
+func (s SummaryDTO) ConvertToModel(histogram []HistogramBucketDTO, items []ItemTotalDTO) *models.Summary {
+	summary := &models.Summary{
+		Count: s.Count,
+		BaseRemuneration: models.DataSummary{
+			Max:         s.BaseMax,
+			Min:         s.BaseMin,
+			Average:     s.BaseAverage,
+			Total:       s.BaseTotal,
+			Percentiles: NewPercentiles(s.BasePercentiles),
+		},
+		OtherRemunerations: models.DataSummary{
+			Max:         s.OtherMax,
+			Min:         s.OtherMin,
+			Average:     s.OtherAverage,
+			Total:       s.OtherTotal,
+			Percentiles: NewPercentiles(s.OtherPercentiles),
+		},
+		Discounts: models.DataSummary{
+			Max:         s.DiscountsMax,
+			Min:         s.DiscountsMin,
+			Average:     s.DiscountsAverage,
+			Total:       s.DiscountsTotal,
+			Percentiles: NewPercentiles(s.DiscountsPercentiles),
+		},
+		Remunerations: models.DataSummary{
+			Max:         s.RemunerationMax,
+			Min:         s.RemunerationMin,
+			Average:     s.RemunerationAvg,
+			Total:       s.RemunerationTotal,
+			Percentiles: NewPercentiles(s.RemunerationPercentiles),
+		},
+		IncomeHistogram: make(map[int]int),
+		ItemSummary:     make(models.ItemSummary),
+	}
+	for _, b := range histogram {
+		summary.IncomeHistogram[b.Bucket] = b.Count
+	}
+	for _, i := range items {
+		summary.ItemSummary[i.Item] = i.Value
+	}
+	return summary
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"github.com/dadosjusbr/storage/models"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// classify envolve um erro vindo do banco de dados em um models.Error, de acordo
+// com o seu tipo (não encontrado, conflito, entrada inválida ou indisponível).
+// Erros já classificados ou que não se encaixam em nenhum tipo são retornados sem alteração.
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	var classified *models.Error
+	if errors.As(err, &classified) {
+		return err
+	}
+	if kind := errorKind(err); kind != nil {
+		return models.NewError(kind, err)
+	}
+	return err
+}
+
+func errorKind(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, sql.ErrNoRows):
+		return models.ErrNotFound
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone), errors.Is(err, context.DeadlineExceeded):
+		return models.ErrUnavailable
+	}
+	// Códigos de erro do postgres: https://www.postgresql.org/docs/current/errcodes-appendix.html
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		case "23": // integrity_constraint_violation
+			if pqErr.Code == "23505" { // unique_violation
+				return models.ErrConflict
+			}
+			return models.ErrInvalidInput
+		case "22": // data_exception
+			return models.ErrInvalidInput
+		case "08", "53", "57": // connection_exception, insufficient_resources, operator_intervention
+			return models.ErrUnavailable
+		}
+		return nil
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return models.ErrUnavailable
+	}
+	return nil
+}
@@ -1,5 +1,13 @@
 \connect dadosjusbr_test
 
+create table versao_esquema
+(
+    versao      integer primary key,
+    aplicada_em timestamp default now()
+);
+
+insert into versao_esquema (versao) values (1), (2), (3), (4), (5), (6), (7);
+
 create table orgaos
 (
     id             varchar(10) primary key,
@@ -44,6 +52,8 @@ create table coletas
     formato_aberto               boolean,
     duracao_segundos             double precision,
     manual                       boolean,
+    impressao_digital            varchar(64),
+    verificado_em                timestamp,
 
     constraint coleta_pk primary key (id,timestamp),
     constraint coleta_orgao_fk foreign key (id_orgao) references orgaos(id) on delete cascade
@@ -101,6 +111,94 @@ create table remuneracoes
     constraint fk_remuneracoes foreign key (id_contracheque, orgao, mes, ano) references contracheques(id, orgao, mes, ano) on delete cascade
 );
 
+create table retroativos
+(
+    id integer,
+    id_contracheque integer,
+    orgao varchar(10),
+    mes integer,
+    ano integer,
+    nome varchar(100),
+    matricula varchar(20),
+    funcao varchar(100),
+    local_trabalho varchar(100),
+    numero_processo varchar(100),
+    objeto_processo text,
+    origem_processo varchar(100),
+    valor_bruto numeric,
+    contribuicao_previdenciaria numeric,
+    imposto_de_renda numeric,
+    abate_teto numeric,
+    descontos numeric,
+    valor_liquido numeric,
+    nome_sanitizado varchar(150),
+
+    constraint retroativos_pk primary key (id, orgao, mes, ano)
+);
+
+create table pacotes
+(
+    chave    text primary key,
+    escopo   varchar(10),
+    id_orgao varchar(10),
+    mes      integer,
+    ano      integer,
+    grupo    varchar(25),
+    pacote   json
+);
+
+create table resumo_rubricas
+(
+    id_orgao varchar(10),
+    mes      integer,
+    ano      integer,
+    rubrica  text,
+    valor    numeric,
+
+    constraint resumo_rubricas_pk primary key (id_orgao, ano, mes, rubrica)
+);
+
+create index resumo_rubricas_ano_idx on resumo_rubricas (ano, mes);
+
+create table teto_remuneratorio
+(
+    ano   integer,
+    mes   integer,
+    valor numeric,
+
+    constraint teto_remuneratorio_pk primary key (ano, mes)
+);
+
+create table indice_precos
+(
+    ano   integer,
+    mes   integer,
+    valor numeric,
+
+    constraint indice_precos_pk primary key (ano, mes)
+);
+
+create table funcoes_normalizadas
+(
+    padrao varchar(150) primary key,
+    funcao varchar(100)
+);
+
+create table anomalias
+(
+    id_orgao     varchar(10),
+    mes          integer,
+    ano          integer,
+    metrica      varchar(25),
+    rubrica      text default '',
+    valor        numeric,
+    mediana      numeric,
+    escore       numeric,
+    detectado_em timestamp,
+
+    constraint anomalias_pk primary key (id_orgao, ano, mes, metrica, rubrica)
+);
+
 CREATE MATERIALIZED VIEW public.media_por_membro
 TABLESPACE pg_default
 AS SELECT media_por_membro.orgao,
@@ -108,7 +206,11 @@ AS SELECT media_por_membro.orgao,
     avg(media_por_membro.salario) AS salario,
     avg(media_por_membro.beneficios) AS beneficios,
     avg(media_por_membro.descontos) AS descontos,
-    avg(media_por_membro.remuneracao) AS remuneracao
+    avg(media_por_membro.remuneracao) AS remuneracao,
+    percentile_cont(ARRAY[0.1, 0.25, 0.5, 0.75, 0.9, 0.99]) WITHIN GROUP (ORDER BY media_por_membro.salario) AS salario_percentis,
+    percentile_cont(ARRAY[0.1, 0.25, 0.5, 0.75, 0.9, 0.99]) WITHIN GROUP (ORDER BY media_por_membro.beneficios) AS beneficios_percentis,
+    percentile_cont(ARRAY[0.1, 0.25, 0.5, 0.75, 0.9, 0.99]) WITHIN GROUP (ORDER BY media_por_membro.descontos) AS descontos_percentis,
+    percentile_cont(ARRAY[0.1, 0.25, 0.5, 0.75, 0.9, 0.99]) WITHIN GROUP (ORDER BY media_por_membro.remuneracao) AS remuneracao_percentis
    FROM ( SELECT c.orgao,
             c.ano,
             c.nome_sanitizado,
@@ -1,17 +1,35 @@
 package database
 
 import (
+	"context"
+
 	"github.com/dadosjusbr/storage/models"
 )
 
 type Interface interface {
 	Connect() error
 	Disconnect() error
-	Store(agmi models.AgencyMonthlyInfo) error
+	// Ping: verifica se a conexão com o banco de dados está funcionando.
+	Ping(ctx context.Context) error
+	// GetSchemaVersion: retorna a versão do esquema (migração) aplicada ao banco de dados ou
+	// ErrSchemaNotVersioned, caso a tabela 'versao_esquema' não exista.
+	GetSchemaVersion(ctx context.Context) (int, error)
+	// GetMissingViews: retorna as views materializadas usadas pelas consultas que não existem no banco de dados.
+	GetMissingViews(ctx context.Context) ([]string, error)
+	// Store: armazena uma nova revisão da coleta, se ela for diferente da atual, e informa se a revisão foi criada.
+	Store(agmi models.AgencyMonthlyInfo) (bool, error)
 	// StorePaychecks: armazena dados nas tabelas 'contracheques' e 'remuneracoes'
 	StorePaychecks(p []models.Paycheck, r []models.PaycheckItem) error
+	// ReplacePaychecks: armazena contracheques e remunerações removendo os que não estão no lote
+	// (para o órgão/mês do lote, que deve ser único) e informa quantos foram inseridos, atualizados e removidos.
+	ReplacePaychecks(p []models.Paycheck, r []models.PaycheckItem) (*models.PaycheckStoreReport, error)
 	// StoreRemunerations: armazena dados dos zips de remunerações que estão no S3.
 	StoreRemunerations(remu models.Remunerations) error
+	// BulkStorePaychecks: armazena contracheques, remunerações e retroativos usando COPY,
+	// para cargas grandes.
+	BulkStorePaychecks(p []models.Paycheck, r []models.PaycheckItem, rp []models.RetroactivePayments) error
+	// StoreCollection: armazena todos os dados de uma coleta em uma única transação (tudo ou nada).
+	StoreCollection(col models.Collection) error
 	GetStateAgencies(uf string) ([]models.Agency, error)
 	// OPJ: Órgãos por jurisdição.
 	GetOPJ(group string) ([]models.Agency, error)
@@ -21,11 +39,42 @@ type Interface interface {
 	GetAgenciesByUF(uf string) ([]models.Agency, error)
 	GetAgency(aid string) (*models.Agency, error)
 	GetAllAgencies() ([]models.Agency, error)
-	GetMonthlyInfo(agencies []models.Agency, year int) (map[string][]models.AgencyMonthlyInfo, error)
-	GetAnnualSummary(agency string) ([]models.AnnualSummary, error)
+	GetMonthlyInfo(agencies []models.Agency, year int, months ...models.MonthRange) (map[string][]models.AgencyMonthlyInfo, error)
+	GetAnnualSummary(agency string, opts ...models.AggregationOpts) ([]models.AnnualSummary, error)
 	// OMA: Órgão Mês Ano
 	GetOMA(month int, year int, agency string) (*models.AgencyMonthlyInfo, *models.Agency, error)
 	GetGeneralMonthlyInfosFromYear(year int) ([]models.GeneralMonthlyInfo, error)
+	// Variantes das consultas acima para um período (mês/ano inicial e final), que pode abranger vários anos.
+	GetMonthlyInfoInPeriod(agencies []models.Agency, period models.Period) (map[string][]models.AgencyMonthlyInfo, error)
+	GetGeneralMonthlyInfosInPeriod(period models.Period, opts ...models.AggregationOpts) ([]models.GeneralMonthlyInfo, error)
+	GetPaychecksInPeriod(agency models.Agency, period models.Period) ([]models.Paycheck, error)
+	GetPaycheckItemsInPeriod(agency models.Agency, period models.Period) ([]models.PaycheckItem, error)
+	// GetGroupedMonthlyInfos: totais mensais agrupados por jurisdição, UF, região ou entidade dos órgãos.
+	GetGroupedMonthlyInfos(groupBy models.AgencyGrouping, period models.Period, opts ...models.AggregationOpts) ([]models.GroupedMonthlyInfo, error)
+	// Tetos remuneratórios, cada um em vigor a partir de um mês/ano até o mês/ano do teto seguinte.
+	StoreRemunerationCaps(caps []models.RemunerationCap) error
+	GetRemunerationCaps() ([]models.RemunerationCap, error)
+	// GetCapExceedances: membros acima do teto remuneratório, por órgão/mês/ano.
+	GetCapExceedances(agencies []models.Agency, period models.Period) ([]models.CapExceedance, error)
+	// Série mensal do índice de preços (IPCA), usada para deflacionar os valores (ver models.AggregationOpts).
+	StorePriceIndex(series []models.PriceIndex) error
+	GetPriceIndex() ([]models.PriceIndex, error)
+	// GetItemTrends: rubricas mais pagas no período, em todos os órgãos ou em um grupo, com a série mensal.
+	GetItemTrends(opts models.ItemRankingOpts, aggOpts ...models.AggregationOpts) ([]models.ItemTrend, error)
+	// StoreAnomalies substitui as anomalias do órgão no período.
+	StoreAnomalies(agencyID string, period models.Period, anomalies []models.Anomaly) error
+	GetAnomalies(agencyID string, period models.Period) ([]models.Anomaly, error)
+	// GetOutlierPaychecks: contracheques muito acima dos contracheques de mesma função normalizada no mês.
+	GetOutlierPaychecks(opts models.OutlierOpts) ([]models.OutlierPaycheck, error)
+	// GetCoverage: meses com coleta, contracheques, zips e inconsistências de cada órgão, desde o primeiro mês monitorado, e meses não publicados segundo os status de coleta.
+	GetCoverage(agencies []models.Agency, until models.MonthYear) ([]models.AgencyCoverage, error)
+	StoreRoleNormalizations(rules []models.RoleNormalization) error
+	GetRoleNormalizations() ([]models.RoleNormalization, error)
+	// GetRemunerationsByRole e GetRemunerationsByWorkplace: totais dos contracheques de um órgão no período por função normalizada ou lotação.
+	GetRemunerationsByRole(agencyID string, period models.Period, opts ...models.AggregationOpts) ([]models.RemunerationGroup, error)
+	GetRemunerationsByWorkplace(agencyID string, period models.Period, opts ...models.AggregationOpts) ([]models.RemunerationGroup, error)
+	// GetRanking: órgãos ordenados por remuneração por membro, proporção de benefícios ou índices de transparência.
+	GetRanking(opts models.RankingOpts) ([]models.RankingEntry, error)
 	GetFirstDateWithMonthlyInfo() (int, int, error)
 	GetLastDateWithMonthlyInfo() (int, int, error)
 	GetGeneralMonthlyInfo() (float64, error)
@@ -33,8 +82,18 @@ type Interface interface {
 	GetAllAgencyCollection(agency string) ([]models.AgencyMonthlyInfo, error)
 	GetPaychecks(agency models.Agency, year int) ([]models.Paycheck, error)
 	GetPaycheckItems(agency models.Agency, year int) ([]models.PaycheckItem, error)
-	GetAveragePerCapita(agency string, year int) (*models.PerCapitaData, error)
+	GetAveragePerCapita(agency string, year int, opts ...models.AggregationOpts) (*models.PerCapitaData, error)
 	GetNotices(agency string, year int, month int) ([]*string, error)
-	GetAveragePerAgency(year int) ([]models.PerCapitaData, error)
+	GetAveragePerAgency(year int, opts ...models.AggregationOpts) ([]models.PerCapitaData, error)
 	GetRetroactivePayments(agency models.Agency, year int, month int) ([]models.RetroactivePayments, error)
+	// DeleteMonthlyData: remove, em uma única transação, todas as revisões da coleta de um órgão/mês/ano
+	// e os dados derivados dela (contracheques, remunerações, retroativos, zips de remunerações,
+	// resumo das rubricas, pacotes mensais e anomalias).
+	DeleteMonthlyData(agency string, month, year int) (*models.DeletionReport, error)
+	// StorePackage: armazena (ou atualiza) o registro de um datapackage no catálogo de pacotes.
+	StorePackage(pkg models.Package) error
+	// GetPackages: consulta o catálogo de pacotes. Campos nulos do filtro não são considerados.
+	GetPackages(opts models.PackageFilterOpts) ([]models.Package, error)
+	// ComputeSummary: calcula o sumário de um órgão/mês/ano a partir das tabelas 'contracheques' e 'remuneracoes'.
+	ComputeSummary(agency string, month, year int) (*models.Summary, error)
 }
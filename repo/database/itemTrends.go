@@ -0,0 +1,141 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/dadosjusbr/storage/models"
+	"github.com/dadosjusbr/storage/repo/database/dto"
+	"gorm.io/gorm"
+)
+
+// defaultItemRankingLimit é o número de rubricas do ranking quando o limite não é informado.
+const defaultItemRankingLimit = 10
+
+// itemKey identifica uma rubrica pelo nome sanitizado (ou original) e pela categoria. As
+// expressões são repetidas no GROUP BY porque 'categoria' também é uma coluna de
+// 'remuneracoes' e, no GROUP BY, o nome resolveria para a coluna, separando as rubricas
+// com categoria nula das com categoria vazia.
+const (
+	itemName     = "COALESCE(r.item_sanitizado, r.item)"
+	itemCategory = "COALESCE(r.categoria, '')"
+)
+
+// GetItemTrends retorna as rubricas de remuneração (tipo R/B ou R/O) mais pagas no período,
+// pelo valor total ou pelo número de membros que as receberam, em todos os órgãos ou nos
+// órgãos de um grupo (ex.: UF SP). As rubricas são identificadas pelo nome sanitizado (ou
+// original, se não houver) e pela categoria. Cada rubrica traz a série mensal do período,
+// com a variação do total em relação ao mês anterior.
+func (p *PostgresDB) GetItemTrends(opts models.ItemRankingOpts, aggOpts ...models.AggregationOpts) ([]models.ItemTrend, error) {
+	var order string
+	switch opts.Metric {
+	case models.RankItemsByTotal:
+		order = "total DESC"
+	case models.RankItemsByRecipients:
+		order = "num_beneficiarios DESC, total DESC"
+	default:
+		return nil, models.NewError(models.ErrInvalidInput, fmt.Errorf("invalid item ranking metric: %q", opts.Metric))
+	}
+	if err := opts.Period.Validate(); err != nil {
+		return nil, err
+	}
+	limit := opts.Limit
+	if limit == 0 {
+		limit = defaultItemRankingLimit
+	}
+	if limit < 0 {
+		return nil, models.NewError(models.ErrInvalidInput, fmt.Errorf("invalid limit: %d", opts.Limit))
+	}
+	var group string
+	if opts.GroupBy != "" {
+		var err error
+		if group, err = agencyGroupColumn(opts.GroupBy); err != nil {
+			return nil, err
+		}
+	}
+	items := func() *gorm.DB {
+		m := p.reader().Table("remuneracoes r").Joins(`JOIN contracheques c ON c.id = r.id_contracheque
+				AND c.orgao = r.orgao
+				AND c.mes = r.mes
+				AND c.ano = r.ano`)
+		m = inPeriod(m.Where("r.tipo LIKE 'R%'"), "r", opts.Period)
+		if group != "" {
+			m = m.Joins("JOIN orgaos ON orgaos.id = r.orgao").Where(group+" = ?", opts.Group)
+		}
+		if opts.Category != nil {
+			m = m.Where("LOWER(r.categoria) = LOWER(?)", *opts.Category)
+		}
+		return m
+	}
+	d, err := p.newDeflation(aggOpts)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.check(items(), "r"); err != nil {
+		return nil, err
+	}
+	totals := fmt.Sprintf(`
+		%s AS rubrica,
+		%s AS categoria,
+		SUM(r.valor * %s) AS total,
+		COUNT(DISTINCT (c.orgao, COALESCE(c.nome_sanitizado, c.nome))) AS num_beneficiarios,
+		COUNT(DISTINCT r.orgao) AS num_orgaos`, itemName, itemCategory, d.factor("r"))
+
+	var dtoTop []dto.ItemTrendDTO
+	m := items().Select(totals).Group(itemName + ", " + itemCategory).Order(order + ", rubrica, categoria").Limit(limit)
+	if err := m.Scan(&dtoTop).Error; err != nil {
+		return nil, fmt.Errorf("error getting item ranking: %w", classify(err))
+	}
+	if len(dtoTop) == 0 {
+		return nil, nil
+	}
+
+	keys := make([][]interface{}, 0, len(dtoTop))
+	for _, t := range dtoTop {
+		keys = append(keys, []interface{}{t.Item, t.Category})
+	}
+	var dtoMonths []dto.ItemTrendDTO
+	m = items().Select(totals+", r.ano, r.mes").Where("("+itemName+", "+itemCategory+") IN ?", keys)
+	m = m.Group(itemName + ", " + itemCategory + ", r.ano, r.mes")
+	if err := m.Scan(&dtoMonths).Error; err != nil {
+		return nil, fmt.Errorf("error getting item monthly totals: %w", classify(err))
+	}
+	type key struct {
+		item, category string
+		month          models.MonthYear
+	}
+	monthly := make(map[key]dto.ItemTrendDTO, len(dtoMonths))
+	for _, t := range dtoMonths {
+		monthly[key{t.Item, t.Category, models.MonthYear{Month: t.Month, Year: t.Year}}] = t
+	}
+
+	// A série tem todos os meses do período, com 0 nos meses em que a rubrica não foi paga.
+	months := opts.Period.Months()
+	trends := make([]models.ItemTrend, 0, len(dtoTop))
+	for _, t := range dtoTop {
+		trend := models.ItemTrend{
+			Item:          t.Item,
+			Category:      t.Category,
+			Total:         t.Total,
+			NumRecipients: t.NumRecipients,
+			NumAgencies:   t.NumAgencies,
+			Months:        make([]models.ItemMonthlyTotal, 0, len(months)),
+		}
+		for i, month := range months {
+			mt := monthly[key{t.Item, t.Category, month}]
+			total := models.ItemMonthlyTotal{
+				Month:         month.Month,
+				Year:          month.Year,
+				Total:         mt.Total,
+				NumRecipients: mt.NumRecipients,
+				NumAgencies:   mt.NumAgencies,
+			}
+			if i > 0 && trend.Months[i-1].Total != 0 {
+				change := (total.Total - trend.Months[i-1].Total) / trend.Months[i-1].Total
+				total.Change = &change
+			}
+			trend.Months = append(trend.Months, total)
+		}
+		trends = append(trends, trend)
+	}
+	return trends, nil
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dadosjusbr/storage/models"
+	"github.com/dadosjusbr/storage/repo/database/dto"
+	"github.com/lib/pq"
+)
+
+// Valores padrão da detecção de contracheques atípicos.
+const (
+	defaultOutlierThreshold = 3.5
+	defaultOutlierMinPeers  = 5
+)
+
+// outlierPaychecks calcula, para a remuneração e para cada rubrica informada, o escore z
+// robusto de cada contracheque em relação aos contracheques com a mesma função normalizada
+// no mês. Contracheques sem a rubrica entram com valor 0. A escala usada no escore está
+// descrita em models.PeerStats.
+const outlierPaychecks = `
+	WITH contracheques_funcao AS (
+		SELECT c.id, c.orgao, c.mes, c.ano, c.nome, c.remuneracao, %[1]s AS funcao
+		FROM contracheques c
+		%[2]s
+		WHERE c.orgao = ? AND c.ano = ? %[3]s
+	),
+	valores AS (
+		SELECT id, orgao, mes, ano, nome, funcao, 'remuneracao' AS motivo, '' AS rubrica, remuneracao AS valor
+		FROM contracheques_funcao
+		UNION ALL
+		SELECT c.id, c.orgao, c.mes, c.ano, c.nome, c.funcao, 'rubrica', i.rubrica, COALESCE(SUM(r.valor), 0)
+		FROM contracheques_funcao c
+		CROSS JOIN unnest(?::text[]) AS i(rubrica)
+		LEFT JOIN remuneracoes r ON r.id_contracheque = c.id
+			AND r.orgao = c.orgao
+			AND r.mes = c.mes
+			AND r.ano = c.ano
+			AND r.item_sanitizado = i.rubrica
+		GROUP BY c.id, c.orgao, c.mes, c.ano, c.nome, c.funcao, i.rubrica
+	),
+	medianas AS (
+		SELECT ano, mes, funcao, motivo, rubrica, COUNT(*) AS num_pares,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY valor) AS mediana
+		FROM valores
+		GROUP BY ano, mes, funcao, motivo, rubrica
+	),
+	desvios AS (
+		SELECT m.ano, m.mes, m.funcao, m.motivo, m.rubrica, m.num_pares, m.mediana,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY ABS(v.valor - m.mediana)) AS mad,
+			AVG(ABS(v.valor - m.mediana)) AS desvio_medio
+		FROM valores v
+		JOIN medianas m USING (ano, mes, funcao, motivo, rubrica)
+		GROUP BY m.ano, m.mes, m.funcao, m.motivo, m.rubrica, m.num_pares, m.mediana
+	),
+	escalas AS (
+		SELECT d.*, COALESCE(
+				NULLIF(1.4826 * d.mad, 0),
+				NULLIF(1.2533 * d.desvio_medio, 0),
+				NULLIF(0.05 * ABS(d.mediana), 0)) AS escala
+		FROM desvios d
+		WHERE d.num_pares >= ?
+	),
+	escores AS (
+		SELECT v.*, e.num_pares, e.mediana, e.mad, e.desvio_medio, e.escala,
+			(v.valor - e.mediana) / e.escala AS escore
+		FROM valores v
+		JOIN escalas e USING (ano, mes, funcao, motivo, rubrica)
+	)
+	SELECT * FROM escores
+	WHERE escore >= ?
+	ORDER BY ano, mes, escore DESC, id, motivo, rubrica`
+
+// GetOutlierPaychecks retorna os contracheques do órgão no ano (ou mês) cuja remuneração, ou
+// o total de uma das rubricas informadas, está muito acima dos contracheques com a mesma
+// função normalizada no mês, com as estatísticas usadas na comparação. Funções com menos
+// contracheques no mês que o mínimo de pares não são analisadas.
+func (p *PostgresDB) GetOutlierPaychecks(opts models.OutlierOpts) ([]models.OutlierPaycheck, error) {
+	threshold, minPeers := opts.Threshold, opts.MinPeers
+	if threshold <= 0 {
+		threshold = defaultOutlierThreshold
+	}
+	if minPeers <= 0 {
+		minPeers = defaultOutlierMinPeers
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	args := []interface{}{strings.ToLower(opts.AgencyID), opts.Year}
+	var month string
+	if opts.Month != nil {
+		month = "AND c.mes = ?"
+		args = append(args, *opts.Month)
+	}
+	args = append(args, pq.StringArray(opts.Items), minPeers, threshold)
+
+	var dtoOutliers []dto.OutlierPaycheckDTO
+	query := fmt.Sprintf(outlierPaychecks, normalizedRole, normalizedRoleJoin, month)
+	if err := p.reader().Raw(query, args...).Scan(&dtoOutliers).Error; err != nil {
+		return nil, fmt.Errorf("error getting outlier paychecks: %w", classify(err))
+	}
+	var outliers []models.OutlierPaycheck
+	for _, o := range dtoOutliers {
+		outliers = append(outliers, *o.ConvertToModel())
+	}
+	return outliers, nil
+}
@@ -3,60 +3,79 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	reflect "reflect"
-	"strconv"
+	"sort"
 	"strings"
-	"time"
+	"sync/atomic"
 
 	"github.com/dadosjusbr/storage/models"
 	"github.com/dadosjusbr/storage/repo/database/dto"
+	"github.com/lib/pq"
 	_ "github.com/newrelic/go-agent/v3/integrations/nrpq"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// SchemaVersion é a versão do esquema (init_db.sql) esperada por esta versão da biblioteca.
+// Deve ser incrementada a cada alteração no esquema, junto com o insert em 'versao_esquema' e
+// a descrição da migração no README (seção Migrações do esquema).
+const SchemaVersion = 7
+
+// ErrSchemaNotVersioned indica que o banco de dados não tem a tabela 'versao_esquema',
+// criada pela migração descrita no README (seção Health check).
+var ErrSchemaNotVersioned = errors.New("schema not versioned: table 'versao_esquema' does not exist")
+
+// materializedViews são as views materializadas usadas pelas consultas.
+var materializedViews = []string{"media_por_membro", "orgao_mes_ano_inconsistentes", "orgao_ano_inconsistentes"}
+
 type PostgresDB struct {
-	db       *gorm.DB
-	user     string
-	password string
-	dbName   string
-	host     string
-	port     string
-	uri      string
+	db          *gorm.DB
+	replicas    *replicaSet
+	lastWrite   atomic.Int64
+	opts        PostgresOptions
+	uri         string
+	replicaURIs []string
 }
 
+// NewPostgresDB cria um client do postgres a partir das credenciais de conexão, sem TLS.
+// Para configurar TLS, pool de conexões, timeouts e réplicas de leitura, use NewPostgresDBWithOptions.
 func NewPostgresDB(user, password, dbName, host, port string) (*PostgresDB, error) {
-	// Verificando se as credenciais de conexão não estão vazias
-	if user == "" {
-		return nil, fmt.Errorf("user cannot be empty")
-	}
-	if password == "" {
-		return nil, fmt.Errorf("password cannot be empty")
-	}
-	if dbName == "" {
-		return nil, fmt.Errorf("dbName cannot be empty")
-	}
-	if host == "" {
-		return nil, fmt.Errorf("host cannot be empty")
+	return NewPostgresDBWithOptions(PostgresOptions{
+		User:     user,
+		Password: password,
+		DBName:   dbName,
+		Host:     host,
+		Port:     port,
+	})
+}
+
+// NewPostgresDBWithOptions cria um client do postgres a partir das opções de conexão.
+// Os erros retornados nunca contêm a senha do banco de dados.
+func NewPostgresDBWithOptions(opts PostgresOptions) (*PostgresDB, error) {
+	// Verificando se as opções de conexão são válidas
+	if err := opts.validate(); err != nil {
+		return nil, models.NewError(models.ErrInvalidInput, err)
 	}
-	if port == "" {
-		return nil, fmt.Errorf("port cannot be empty")
+	uri, err := opts.dsn()
+	if err != nil {
+		return nil, models.NewError(models.ErrInvalidInput, err)
 	}
-
-	uri := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=disable", host, port, user, dbName, password)
 	postgresDB := &PostgresDB{
-		user:     user,
-		password: password,
-		dbName:   dbName,
-		host:     host,
-		port:     port,
-		uri:      uri,
+		opts: opts,
+		uri:  uri,
+	}
+	for _, r := range opts.Replicas {
+		replicaURI, err := opts.replicaDSN(r)
+		if err != nil {
+			return nil, models.NewError(models.ErrInvalidInput, err)
+		}
+		postgresDB.replicaURIs = append(postgresDB.replicaURIs, replicaURI)
 	}
 	//Conectando ao postgres
 	if err := postgresDB.Connect(); err != nil {
-		return nil, fmt.Errorf("error connecting to postgres (creds:%s):%q", uri, err)
+		return nil, fmt.Errorf("error connecting to postgres (%s): %w", redactDSN(uri), err)
 	}
 	return postgresDB, nil
 }
@@ -64,42 +83,90 @@ func NewPostgresDB(user, password, dbName, host, port string) (*PostgresDB, erro
 func (p *PostgresDB) Connect() error {
 	if p.db != nil {
 		return nil
-	} else {
-		conn, err := sql.Open("nrpostgres", p.uri)
+	}
+	db, err := p.open(p.uri)
+	if err != nil {
+		return err
+	}
+	timeout := p.opts.ConnectTimeout
+	if timeout == 0 {
+		timeout = defaultConnectTimeout
+	}
+	ctx, canc := context.WithTimeout(context.Background(), timeout)
+	defer canc()
+	if err := ping(ctx, db); err != nil {
+		closeAll(db)
+		return fmt.Errorf("error pinging postgres: %w", p.redactError(models.NewError(models.ErrUnavailable, err)))
+	}
+	// As réplicas que não estiverem acessíveis são marcadas como não saudáveis e as
+	// leituras são direcionadas ao banco primário até que elas voltem.
+	var replicas []*gorm.DB
+	for _, uri := range p.replicaURIs {
+		r, err := p.open(uri)
 		if err != nil {
-			panic(err)
-		}
-		ctx, canc := context.WithTimeout(context.Background(), 30*time.Second)
-		defer canc()
-		if err := conn.PingContext(ctx); err != nil {
-			return fmt.Errorf("error connecting to postgres (creds:%s):%q", p.uri, err)
+			closeAll(append(replicas, db)...)
+			return fmt.Errorf("error connecting to replica (%s): %w", redactDSN(uri), err)
 		}
-		db, err := gorm.Open(postgres.New(postgres.Config{
-			Conn: conn,
-		}))
-		if err != nil {
-			return fmt.Errorf("error initializing gorm: %q", err)
+		replicas = append(replicas, r)
+	}
+	p.db = db
+	if len(replicas) > 0 {
+		p.replicas = newReplicaSet(replicas)
+		p.replicas.start(p.opts.ReplicaHealthCheckInterval)
+	}
+	return nil
+}
+
+// open abre um pool de conexões com o banco de dados, sem estabelecer nenhuma conexão.
+func (p *PostgresDB) open(uri string) (*gorm.DB, error) {
+	conn, err := sql.Open("nrpostgres", uri)
+	if err != nil {
+		return nil, fmt.Errorf("error opening postgres connection: %w", p.redactError(models.NewError(models.ErrInvalidInput, err)))
+	}
+	// Configurando o pool de conexões
+	conn.SetMaxOpenConns(p.opts.MaxOpenConns)
+	if p.opts.MaxIdleConns > 0 {
+		conn.SetMaxIdleConns(p.opts.MaxIdleConns)
+	}
+	conn.SetConnMaxLifetime(p.opts.ConnMaxLifetime)
+	conn.SetConnMaxIdleTime(p.opts.ConnMaxIdleTime)
+
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: conn,
+	}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error initializing gorm: %w", p.redactError(classify(err)))
+	}
+	return db, nil
+}
+
+func closeAll(dbs ...*gorm.DB) {
+	for _, db := range dbs {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
 		}
-		p.db = db
-		return nil
 	}
 }
 
 func (p *PostgresDB) Disconnect() error {
+	if err := p.replicas.close(); err != nil {
+		return fmt.Errorf("error closing replicas connections: %w", classify(err))
+	}
 	db, err := p.db.DB()
 	if err != nil {
-		return fmt.Errorf("error returning sql DB: %q", err)
+		return fmt.Errorf("error returning sql DB: %w", classify(err))
 	}
 	err = db.Close()
 	if err != nil {
-		return fmt.Errorf("error closing DB connection: %q", err)
+		return fmt.Errorf("error closing DB connection: %w", classify(err))
 	}
 	return nil
 }
 
 func (p *PostgresDB) GetConnection() (*gorm.DB, error) {
 	if p.db == nil {
-		return nil, fmt.Errorf("database not connected!")
+		return nil, models.NewError(models.ErrUnavailable, fmt.Errorf("database not connected!"))
 	}
 	return p.db, nil
 }
@@ -108,50 +175,138 @@ func (p *PostgresDB) SetConnection(conn *gorm.DB) {
 	p.db = conn
 }
 
-func (p *PostgresDB) Store(agmi models.AgencyMonthlyInfo) error {
+func (p *PostgresDB) Ping(ctx context.Context) error {
+	if p.db == nil {
+		return models.NewError(models.ErrUnavailable, fmt.Errorf("database not connected!"))
+	}
+	if err := ping(ctx, p.db); err != nil {
+		return fmt.Errorf("error pinging postgres: %w", p.redactError(models.NewError(models.ErrUnavailable, err)))
+	}
+	return nil
+}
+
+func (p *PostgresDB) GetSchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	if err := p.db.WithContext(ctx).Raw("SELECT COALESCE(MAX(versao), 0) FROM versao_esquema").Scan(&version).Error; err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "42P01" { // undefined_table
+			return 0, models.NewError(models.ErrNotFound, ErrSchemaNotVersioned)
+		}
+		return 0, fmt.Errorf("error getting schema version: %w", classify(err))
+	}
+	return version, nil
+}
+
+func (p *PostgresDB) GetMissingViews(ctx context.Context) ([]string, error) {
+	var views []string
+	if err := p.db.WithContext(ctx).Raw("SELECT matviewname FROM pg_matviews WHERE matviewname IN ?", materializedViews).Scan(&views).Error; err != nil {
+		return nil, fmt.Errorf("error getting materialized views: %w", classify(err))
+	}
+	existing := make(map[string]bool, len(views))
+	for _, v := range views {
+		existing[v] = true
+	}
+	var missing []string
+	for _, v := range materializedViews {
+		if !existing[v] {
+			missing = append(missing, v)
+		}
+	}
+	return missing, nil
+}
+
+// Store armazena uma nova revisão da coleta e retorna se ela foi criada. Se a coleta
+// for idêntica à revisão atual (mesma impressão digital), nenhuma revisão é criada:
+// apenas a data da última verificação da revisão atual é atualizada.
+func (p *PostgresDB) Store(agmi models.AgencyMonthlyInfo) (bool, error) {
+	defer p.markWrite()
+	/* Iniciando a transação. É necessário que seja uma transação porque queremos
+	executar vários scripts que são dependentes um do outro. Ou seja, se um falhar
+	todos falham. Isso nos dá uma maior segurança ao executar a inserção. */
+	var created bool
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		created, err = saveMonthlyInfo(tx, agmi)
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("error performing transaction: %w", classify(err))
+	}
+
+	return created, nil
+}
+
+// saveMonthlyInfo insere uma nova revisão da coleta, marcando as revisões anteriores
+// como não atuais, e retorna se a revisão foi criada. Deve ser executada dentro de uma transação.
+func saveMonthlyInfo(tx *gorm.DB, agmi models.AgencyMonthlyInfo) (bool, error) {
 	/*Criando o DTO da coleta a partir de um modelo. É necessário a utilização de
 	DTO's para melhor escalabilidade de bancos de dados. Caso não fosse utilizado,
 	não seria possível utilizar outros frameworks/bancos além do GORM, pois ele
 	afeta diretamente os tipos e campos de uma struct.*/
 	coletas, err := dto.NewAgencyMonthlyInfoDTO(agmi)
 	if err != nil {
-		return fmt.Errorf("error converting agency monthly info to dto: %q", err)
+		return false, models.NewError(models.ErrInvalidInput, fmt.Errorf("error converting agency monthly info to dto: %w", err))
 	}
+	ID := fmt.Sprintf("%s/%s/%d", agmi.AgencyID, dto.AddZeroes(agmi.Month), agmi.Year)
 
-	/* Iniciando a transação. É necessário que seja uma transação porque queremos
-	executar vários scripts que são dependentes um do outro. Ou seja, se um falhar
-	todos falham. Isso nos dá uma maior segurança ao executar a inserção. */
-	err = p.db.Transaction(func(tx *gorm.DB) error {
-		// Definindo atual como false para todos os registros com o mesmo ID.
-		ID := fmt.Sprintf("%s/%s/%d", agmi.AgencyID, dto.AddZeroes(agmi.Month), agmi.Year)
-		if err := tx.Model(dto.AgencyMonthlyInfoDTO{}).Where("id = ?", ID).Update("atual", false).Error; err != nil {
-			return fmt.Errorf("error seting 'atual' to false: %q", err)
+	// Coletas que falharam (com procinfo) sempre geram uma nova revisão. As demais só
+	// geram uma nova revisão se o conteúdo for diferente do da revisão atual.
+	if agmi.ProcInfo == nil {
+		var current []dto.AgencyMonthlyInfoDTO
+		if err := tx.Model(dto.AgencyMonthlyInfoDTO{}).Where("id = ? AND atual = true", ID).Find(&current).Error; err != nil {
+			return false, fmt.Errorf("error getting current 'coleta': %w", classify(err))
 		}
-
-		if err := tx.Model(dto.AgencyMonthlyInfoDTO{}).Create(coletas).Error; err != nil {
-			return fmt.Errorf("error inserting 'coleta': %q", err)
+		if len(current) == 1 && current[0].Fingerprint == coletas.Fingerprint {
+			if err := tx.Model(dto.AgencyMonthlyInfoDTO{}).Where("id = ? AND atual = true", ID).Update("verificado_em", coletas.VerifiedAt).Error; err != nil {
+				return false, fmt.Errorf("error updating 'verificado_em': %w", classify(err))
+			}
+			return false, nil
 		}
+	}
 
-		return nil
-	})
-	if err != nil {
-		return fmt.Errorf("error performing transaction: %q", err)
+	// Definindo atual como false para todos os registros com o mesmo ID.
+	if err := tx.Model(dto.AgencyMonthlyInfoDTO{}).Where("id = ?", ID).Update("atual", false).Error; err != nil {
+		return false, fmt.Errorf("error seting 'atual' to false: %w", classify(err))
 	}
 
-	return nil
+	if err := tx.Model(dto.AgencyMonthlyInfoDTO{}).Create(coletas).Error; err != nil {
+		return false, fmt.Errorf("error inserting 'coleta': %w", classify(err))
+	}
+
+	// A tabela 'resumo_rubricas' guarda apenas as rubricas da revisão atual.
+	if err := tx.Where("id_orgao = ? AND mes = ? AND ano = ?", agmi.AgencyID, agmi.Month, agmi.Year).Delete(&dto.ItemSummaryDTO{}).Error; err != nil {
+		return false, fmt.Errorf("error deleting 'resumo_rubricas': %w", classify(err))
+	}
+	if items := dto.NewItemSummaryDTOs(agmi); len(items) > 0 {
+		if err := tx.Create(&items).Error; err != nil {
+			return false, fmt.Errorf("error inserting 'resumo_rubricas': %w", classify(err))
+		}
+	}
+
+	return true, nil
 }
 
 func (p *PostgresDB) StorePaychecks(paychecks []models.Paycheck, remunerations []models.PaycheckItem) error {
+	defer p.markWrite()
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		return savePaychecks(tx, paychecks, remunerations)
+	})
+}
+
+// savePaychecks insere (ou atualiza) os contracheques e o detalhamento das remunerações.
+func savePaychecks(tx *gorm.DB, paychecks []models.Paycheck, remunerations []models.PaycheckItem) error {
 	// Armazenando contracheques
-	var payc []*dto.PaycheckDTO
-	for _, pc := range paychecks {
-		payc = append(payc, dto.NewPaycheckDTO(pc))
-	}
-	if err := p.db.Model(dto.PaycheckDTO{}).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "orgao"}, {Name: "mes"}, {Name: "ano"}, {Name: "id"}},
-		UpdateAll: true,
-	}).Create(payc).Error; err != nil {
-		return fmt.Errorf("error inserting 'contracheques': %w", err)
+	if len(paychecks) != 0 {
+		var payc []*dto.PaycheckDTO
+		for _, pc := range paychecks {
+			payc = append(payc, dto.NewPaycheckDTO(pc))
+		}
+		if err := tx.Model(dto.PaycheckDTO{}).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "orgao"}, {Name: "mes"}, {Name: "ano"}, {Name: "id"}},
+			UpdateAll: true,
+		}).Create(payc).Error; err != nil {
+			return fmt.Errorf("error inserting 'contracheques': %w", classify(err))
+		}
 	}
 
 	// Armazenando o detalhamento das remunerações
@@ -160,29 +315,342 @@ func (p *PostgresDB) StorePaychecks(paychecks []models.Paycheck, remunerations [
 		for _, r := range remunerations {
 			rem = append(rem, dto.NewPaycheckItemDTO(r))
 		}
-		if err := p.db.Model(dto.PaycheckItemDTO{}).Clauses(clause.OnConflict{
+		if err := tx.Model(dto.PaycheckItemDTO{}).Clauses(clause.OnConflict{
 			Columns:   []clause.Column{{Name: "orgao"}, {Name: "mes"}, {Name: "ano"}, {Name: "id"}, {Name: "id_contracheque"}},
 			UpdateAll: true,
 		}).CreateInBatches(rem, 5000).Error; err != nil {
-			return fmt.Errorf("error inserting 'remuneracoes': %w", err)
+			return fmt.Errorf("error inserting 'remuneracoes': %w", classify(err))
+		}
+	}
+
+	return nil
+}
+
+// agencyMonth identifica os dados de um órgão em um mês/ano.
+type agencyMonth struct {
+	agency string
+	month  int
+	year   int
+}
+
+// paycheckItemKey identifica um item de remuneração dentro de um órgão/mês/ano.
+type paycheckItemKey struct {
+	id         int
+	paycheckID int
+}
+
+// ReplacePaychecks armazena os contracheques e itens de remuneração substituindo os dados
+// do órgão/mês do lote: contracheques e itens desse órgão/mês que não estão no lote são
+// removidos. Tudo é feito em uma única transação. O lote deve pertencer a um único
+// órgão/mês, o que é verificado pelo Client (lotes com vários órgãos/meses são rejeitados
+// antes de chegar ao banco de dados).
+func (p *PostgresDB) ReplacePaychecks(paychecks []models.Paycheck, remunerations []models.PaycheckItem) (*models.PaycheckStoreReport, error) {
+	defer p.markWrite()
+	// Agrupando o lote por órgão/mês/ano.
+	newPaychecks := make(map[agencyMonth]map[int]struct{})
+	newItems := make(map[agencyMonth]map[paycheckItemKey]struct{})
+	for _, pc := range paychecks {
+		am := agencyMonth{agency: pc.Agency, month: pc.Month, year: pc.Year}
+		if newPaychecks[am] == nil {
+			newPaychecks[am] = make(map[int]struct{})
 		}
+		newPaychecks[am][pc.ID] = struct{}{}
 	}
+	for _, r := range remunerations {
+		am := agencyMonth{agency: r.Agency, month: r.Month, year: r.Year}
+		if newItems[am] == nil {
+			newItems[am] = make(map[paycheckItemKey]struct{})
+		}
+		newItems[am][paycheckItemKey{id: r.ID, paycheckID: r.PaycheckID}] = struct{}{}
+		if _, ok := newPaychecks[am]; !ok {
+			newPaychecks[am] = make(map[int]struct{})
+		}
+	}
+
+	report := &models.PaycheckStoreReport{}
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		for am, ids := range newPaychecks {
+			var existingIDs []int
+			if err := tx.Model(dto.PaycheckDTO{}).Where("orgao = ? AND mes = ? AND ano = ?", am.agency, am.month, am.year).Pluck("id", &existingIDs).Error; err != nil {
+				return fmt.Errorf("error getting 'contracheques' ids: %w", classify(err))
+			}
+			var existingItems []dto.PaycheckItemDTO
+			if err := tx.Model(dto.PaycheckItemDTO{}).Select("id, id_contracheque").Where("orgao = ? AND mes = ? AND ano = ?", am.agency, am.month, am.year).Find(&existingItems).Error; err != nil {
+				return fmt.Errorf("error getting 'remuneracoes' ids: %w", classify(err))
+			}
+
+			// Removendo primeiro os itens que não estão no lote (inclusive os dos
+			// contracheques que serão removidos) e depois os contracheques.
+			var staleItemIDs, staleItemPaycheckIDs []int64
+			existingItemKeys := make(map[paycheckItemKey]struct{})
+			for _, item := range existingItems {
+				key := paycheckItemKey{id: item.ID, paycheckID: item.PaycheckID}
+				existingItemKeys[key] = struct{}{}
+				if _, ok := newItems[am][key]; !ok {
+					staleItemIDs = append(staleItemIDs, int64(key.id))
+					staleItemPaycheckIDs = append(staleItemPaycheckIDs, int64(key.paycheckID))
+				}
+			}
+			if len(staleItemIDs) > 0 {
+				res := tx.Where(`orgao = ? AND mes = ? AND ano = ?
+						AND (id, id_contracheque) IN (SELECT * FROM unnest(?::int[], ?::int[]))`,
+					am.agency, am.month, am.year, pq.Int64Array(staleItemIDs), pq.Int64Array(staleItemPaycheckIDs)).Delete(&dto.PaycheckItemDTO{})
+				if res.Error != nil {
+					return fmt.Errorf("error deleting stale 'remuneracoes': %w", classify(res.Error))
+				}
+				report.ItemsDeleted += int(res.RowsAffected)
+			}
+
+			var stalePaycheckIDs []int64
+			existingPaycheckIDs := make(map[int]struct{})
+			for _, id := range existingIDs {
+				existingPaycheckIDs[id] = struct{}{}
+				if _, ok := ids[id]; !ok {
+					stalePaycheckIDs = append(stalePaycheckIDs, int64(id))
+				}
+			}
+			if len(stalePaycheckIDs) > 0 {
+				res := tx.Where("orgao = ? AND mes = ? AND ano = ? AND id = ANY(?)",
+					am.agency, am.month, am.year, pq.Int64Array(stalePaycheckIDs)).Delete(&dto.PaycheckDTO{})
+				if res.Error != nil {
+					return fmt.Errorf("error deleting stale 'contracheques': %w", classify(res.Error))
+				}
+				report.PaychecksDeleted += int(res.RowsAffected)
+			}
+
+			for id := range ids {
+				if _, ok := existingPaycheckIDs[id]; ok {
+					report.PaychecksUpdated++
+				} else {
+					report.PaychecksInserted++
+				}
+			}
+			for key := range newItems[am] {
+				if _, ok := existingItemKeys[key]; ok {
+					report.ItemsUpdated++
+				} else {
+					report.ItemsInserted++
+				}
+			}
+		}
+		return savePaychecks(tx, paychecks, remunerations)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error performing transaction: %w", classify(err))
+	}
+	return report, nil
+}
+
+// saveRetroactivePayments insere (ou atualiza) os pagamentos retroativos.
+func saveRetroactivePayments(tx *gorm.DB, payments []models.RetroactivePayments) error {
+	if len(payments) == 0 {
+		return nil
+	}
+	var retro []*dto.RetroactivePaymentsDTO
+	for _, rp := range payments {
+		retro = append(retro, dto.NewRetroactivePaymentsDTO(rp))
+	}
+	if err := tx.Model(dto.RetroactivePaymentsDTO{}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "orgao"}, {Name: "mes"}, {Name: "ano"}, {Name: "id"}},
+		UpdateAll: true,
+	}).CreateInBatches(retro, 5000).Error; err != nil {
+		return fmt.Errorf("error inserting 'retroativos': %w", classify(err))
+	}
+	return nil
+}
+
+// Colunas usadas no carregamento em massa (COPY) de cada tabela.
+var (
+	paycheckColumns = []string{"id", "orgao", "mes", "ano", "chave_coleta", "nome", "matricula", "funcao",
+		"local_trabalho", "salario", "beneficios", "descontos", "remuneracao", "situacao", "nome_sanitizado"}
+	paycheckItemColumns = []string{"id", "id_contracheque", "orgao", "mes", "ano", "tipo", "categoria", "item",
+		"valor", "inconsistente", "item_sanitizado"}
+	retroactivePaymentColumns = []string{"id", "id_contracheque", "orgao", "mes", "ano", "nome", "matricula", "funcao",
+		"local_trabalho", "numero_processo", "objeto_processo", "origem_processo", "valor_bruto",
+		"contribuicao_previdenciaria", "imposto_de_renda", "abate_teto", "descontos", "valor_liquido", "nome_sanitizado"}
+)
+
+// BulkStorePaychecks armazena contracheques, remunerações e retroativos usando COPY.
+// Os dados são copiados para tabelas temporárias (staging) e depois mesclados nas
+// tabelas definitivas com um único INSERT ... ON CONFLICT por tabela, o que é bem mais
+// rápido que inserções em lote e mantém as tabelas bloqueadas por menos tempo.
+// Tudo é feito em uma única transação.
+func (p *PostgresDB) BulkStorePaychecks(paychecks []models.Paycheck, remunerations []models.PaycheckItem, retroactive []models.RetroactivePayments) error {
+	defer p.markWrite()
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		if len(paychecks) != 0 {
+			rows := make([][]interface{}, 0, len(paychecks))
+			for _, pc := range paychecks {
+				d := dto.NewPaycheckDTO(pc)
+				rows = append(rows, []interface{}{d.ID, d.Agency, d.Month, d.Year, d.CollectKey, d.Name, d.RegisterID, d.Role,
+					d.Workplace, d.Salary, d.Benefits, d.Discounts, d.Remuneration, d.Situation, d.SanitizedName})
+			}
+			if err := copyAndMerge(tx, dto.PaycheckDTO{}.TableName(), paycheckColumns, []string{"id", "orgao", "mes", "ano"}, rows); err != nil {
+				return err
+			}
+		}
+		if len(remunerations) != 0 {
+			rows := make([][]interface{}, 0, len(remunerations))
+			for _, r := range remunerations {
+				d := dto.NewPaycheckItemDTO(r)
+				rows = append(rows, []interface{}{d.ID, d.PaycheckID, d.Agency, d.Month, d.Year, d.Type, d.Category, d.Item,
+					d.Value, d.Inconsistent, d.SanitizedItem})
+			}
+			if err := copyAndMerge(tx, dto.PaycheckItemDTO{}.TableName(), paycheckItemColumns, []string{"id", "id_contracheque", "orgao", "mes", "ano"}, rows); err != nil {
+				return err
+			}
+		}
+		if len(retroactive) != 0 {
+			rows := make([][]interface{}, 0, len(retroactive))
+			for _, rp := range retroactive {
+				d := dto.NewRetroactivePaymentsDTO(rp)
+				rows = append(rows, []interface{}{d.ID, d.PaycheckID, d.Agency, d.Month, d.Year, d.Name, d.RegisterID, d.Role,
+					d.Workplace, d.ProcessNumber, d.ProcessObject, d.ProcessOrigin, d.Value, d.SocialContribution,
+					d.IncomeTax, d.SalaryCapDeduction, d.Discounts, d.NetValue, d.SanitizedName})
+			}
+			if err := copyAndMerge(tx, dto.RetroactivePaymentsDTO{}.TableName(), retroactivePaymentColumns, []string{"id", "orgao", "mes", "ano"}, rows); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error performing transaction: %w", classify(err))
+	}
+	return nil
+}
+
+// prepareCopy prepara o COPY das colunas para a tabela. É uma variável para que os testes
+// possam simular um driver sem suporte a COPY.
+var prepareCopy = func(tx *gorm.DB, table string, columns []string) (*sql.Stmt, error) {
+	return tx.Statement.ConnPool.PrepareContext(tx.Statement.Context, pq.CopyIn(table, columns...))
+}
+
+// maxInsertParams é o número máximo de parâmetros de um INSERT em lote (o limite do postgres é 65535).
+const maxInsertParams = 65000
+
+// copyAndMerge copia as linhas para uma tabela temporária com a mesma estrutura de table,
+// usando COPY, e as mescla em table (inserindo ou atualizando pelas colunas de conflito).
+// Se o driver não suportar COPY (a preparação falha), as linhas são copiadas para a tabela
+// temporária com INSERTs em lote. Deve ser executada dentro de uma transação: a tabela
+// temporária é removida no commit.
+func copyAndMerge(tx *gorm.DB, table string, columns, conflict []string, rows [][]interface{}) error {
+	stage := table + "_stage"
+	if err := tx.Exec(fmt.Sprintf("CREATE TEMP TABLE %s (LIKE %s INCLUDING DEFAULTS) ON COMMIT DROP", stage, table)).Error; err != nil {
+		return fmt.Errorf("error creating staging table for '%s': %w", table, classify(err))
+	}
+
+	// Se a preparação do COPY falhar no servidor, a transação é abortada; o savepoint permite continuar.
+	if err := tx.SavePoint(stage).Error; err != nil {
+		return fmt.Errorf("error creating savepoint for '%s': %w", stage, classify(err))
+	}
+	stmt, err := prepareCopy(tx, stage, columns)
+	if err != nil {
+		if err := tx.RollbackTo(stage).Error; err != nil {
+			return fmt.Errorf("error rolling back to savepoint for '%s': %w", stage, classify(err))
+		}
+		if err := insertRows(tx, stage, columns, rows); err != nil {
+			return err
+		}
+	} else if err := copyRows(tx, stmt, stage, rows); err != nil {
+		return err
+	}
+
+	var updates []string
+	for _, col := range columns {
+		updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", col, col))
+	}
+	cols := strings.Join(columns, ", ")
+	merge := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s ON CONFLICT (%s) DO UPDATE SET %s",
+		table, cols, cols, stage, strings.Join(conflict, ", "), strings.Join(updates, ", "))
+	if err := tx.Exec(merge).Error; err != nil {
+		return fmt.Errorf("error merging '%s' into '%s': %w", stage, table, classify(err))
+	}
+	return nil
+}
+
+// copyRows envia as linhas ao COPY preparado e o finaliza.
+func copyRows(tx *gorm.DB, stmt *sql.Stmt, stage string, rows [][]interface{}) error {
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(tx.Statement.Context, row...); err != nil {
+			stmt.Close()
+			return fmt.Errorf("error copying row into '%s': %w", stage, classify(err))
+		}
+	}
+	// Uma chamada sem argumentos envia os dados pendentes ao banco.
+	if _, err := stmt.ExecContext(tx.Statement.Context); err != nil {
+		stmt.Close()
+		return fmt.Errorf("error flushing copy into '%s': %w", stage, classify(err))
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("error closing copy into '%s': %w", stage, classify(err))
+	}
+	return nil
+}
+
+// insertRows insere as linhas na tabela com INSERTs em lote, alternativa ao COPY.
+func insertRows(tx *gorm.DB, table string, columns []string, rows [][]interface{}) error {
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	batch := maxInsertParams / len(columns)
+	for start := 0; start < len(rows); start += batch {
+		end := start + batch
+		if end > len(rows) {
+			end = len(rows)
+		}
+		values := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*len(columns))
+		for _, row := range rows[start:end] {
+			values = append(values, placeholders)
+			args = append(args, row...)
+		}
+		insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", table, strings.Join(columns, ", "), strings.Join(values, ", "))
+		if err := tx.Exec(insert, args...).Error; err != nil {
+			return fmt.Errorf("error inserting rows into '%s': %w", table, classify(err))
+		}
+	}
+	return nil
+}
 
+// StoreCollection armazena todos os dados de uma coleta (informações mensais, contracheques,
+// remunerações, retroativos e metadados do zip de remunerações) em uma única transação.
+// Se qualquer uma das inserções falhar, nada é armazenado.
+func (p *PostgresDB) StoreCollection(col models.Collection) error {
+	defer p.markWrite()
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := saveMonthlyInfo(tx, col.MonthlyInfo); err != nil {
+			return err
+		}
+		if err := savePaychecks(tx, col.Paychecks, col.PaycheckItems); err != nil {
+			return err
+		}
+		if err := saveRetroactivePayments(tx, col.RetroactivePayments); err != nil {
+			return err
+		}
+		if col.Remunerations != nil {
+			if err := saveRemunerations(tx, *col.Remunerations); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error performing transaction: %w", classify(err))
+	}
 	return nil
 }
 
 func (p *PostgresDB) GetStateAgencies(uf string) ([]models.Agency, error) {
 	uf = strings.ToUpper(uf)
 	var dtoOrgaos []dto.AgencyDTO
-	if err := p.db.Model(&dto.AgencyDTO{}).Where("jurisdicao = 'Estadual' AND uf = ?", uf).Find(&dtoOrgaos).Error; err != nil {
-		return nil, fmt.Errorf("error getting agencies: %q", err)
+	if err := p.reader().Model(&dto.AgencyDTO{}).Where("jurisdicao = 'Estadual' AND uf = ?", uf).Find(&dtoOrgaos).Error; err != nil {
+		return nil, fmt.Errorf("error getting agencies: %w", classify(err))
 	}
 
 	var orgaos []models.Agency
 	for _, dtoOrgao := range dtoOrgaos {
 		orgao, err := dtoOrgao.ConvertToModel()
 		if err != nil {
-			return nil, fmt.Errorf("error converting agency dto to model: %q", err)
+			return nil, fmt.Errorf("error converting agency dto to model: %w", err)
 		}
 		orgaos = append(orgaos, *orgao)
 	}
@@ -192,15 +660,15 @@ func (p *PostgresDB) GetStateAgencies(uf string) ([]models.Agency, error) {
 func (p *PostgresDB) GetOPJ(group string) ([]models.Agency, error) {
 	var dtoOrgaos []dto.AgencyDTO
 	group = strings.ToLower(group)
-	if err := p.db.Model(&dto.AgencyDTO{}).Where("LOWER(jurisdicao) = ?", group).Find(&dtoOrgaos).Error; err != nil {
-		return nil, fmt.Errorf("error getting agencies by type: %q", err)
+	if err := p.reader().Model(&dto.AgencyDTO{}).Where("LOWER(jurisdicao) = ?", group).Find(&dtoOrgaos).Error; err != nil {
+		return nil, fmt.Errorf("error getting agencies by type: %w", classify(err))
 	}
 
 	var orgaos []models.Agency
 	for _, dtoOrgao := range dtoOrgaos {
 		orgao, err := dtoOrgao.ConvertToModel()
 		if err != nil {
-			return nil, fmt.Errorf("error converting agency dto to model: %q", err)
+			return nil, fmt.Errorf("error converting agency dto to model: %w", err)
 		}
 		orgaos = append(orgaos, *orgao)
 	}
@@ -208,36 +676,41 @@ func (p *PostgresDB) GetOPJ(group string) ([]models.Agency, error) {
 }
 
 func (p *PostgresDB) StoreRemunerations(remu models.Remunerations) error {
+	defer p.markWrite()
+	return saveRemunerations(p.db, remu)
+}
+
+func saveRemunerations(tx *gorm.DB, remu models.Remunerations) error {
 	remuneracoes := dto.NewRemunerationsDTO(remu)
-	if err := p.db.Model(dto.RemunerationsDTO{}).Clauses(clause.OnConflict{
+	if err := tx.Model(dto.RemunerationsDTO{}).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "id_orgao"}, {Name: "mes"}, {Name: "ano"}},
 		UpdateAll: true,
 	}).Create(remuneracoes).Error; err != nil {
-		return fmt.Errorf("error inserting 'remuneracoes_zips': %q", err)
+		return fmt.Errorf("error inserting 'remuneracoes_zips': %w", classify(err))
 	}
 	return nil
 }
 
 func (p *PostgresDB) GetAgenciesCount() (int, error) {
 	var count int64
-	if err := p.db.Model(&dto.AgencyDTO{}).Count(&count).Error; err != nil {
-		return 0, fmt.Errorf("error getting agencies count: %q", err)
+	if err := p.reader().Model(&dto.AgencyDTO{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("error getting agencies count: %w", classify(err))
 	}
 	return int(count), nil
 }
 
 func (p *PostgresDB) GetNumberOfMonthsCollected() (int, error) {
 	var count int64
-	if err := p.db.Model(&dto.AgencyMonthlyInfoDTO{}).Where("atual = true").Count(&count).Error; err != nil {
-		return 0, fmt.Errorf("error getting agencies count: %q", err)
+	if err := p.reader().Model(&dto.AgencyMonthlyInfoDTO{}).Where("atual = true").Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("error getting agencies count: %w", classify(err))
 	}
 	return int(count), nil
 }
 
 func (p *PostgresDB) GetNumberOfPaychecksCollected() (int, error) {
 	var count int64
-	if err := p.db.Model(&dto.PaycheckDTO{}).Count(&count).Error; err != nil {
-		return 0, fmt.Errorf("error getting paychecks count: %q", err)
+	if err := p.reader().Model(&dto.PaycheckDTO{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("error getting paychecks count: %w", classify(err))
 	}
 	return int(count), nil
 }
@@ -245,14 +718,14 @@ func (p *PostgresDB) GetNumberOfPaychecksCollected() (int, error) {
 func (p *PostgresDB) GetAgenciesByUF(uf string) ([]models.Agency, error) {
 	var dtoOrgaos []dto.AgencyDTO
 	uf = strings.ToUpper(uf)
-	if err := p.db.Model(&dto.AgencyDTO{}).Where("uf = ?", uf).Find(&dtoOrgaos).Error; err != nil {
-		return nil, fmt.Errorf("error getting agencies: %q", err)
+	if err := p.reader().Model(&dto.AgencyDTO{}).Where("uf = ?", uf).Find(&dtoOrgaos).Error; err != nil {
+		return nil, fmt.Errorf("error getting agencies: %w", classify(err))
 	}
 	var orgaos []models.Agency
 	for _, dtoOrgao := range dtoOrgaos {
 		orgao, err := dtoOrgao.ConvertToModel()
 		if err != nil {
-			return nil, fmt.Errorf("error converting agency dto to model: %q", err)
+			return nil, fmt.Errorf("error converting agency dto to model: %w", err)
 		}
 		orgaos = append(orgaos, *orgao)
 	}
@@ -262,237 +735,218 @@ func (p *PostgresDB) GetAgenciesByUF(uf string) ([]models.Agency, error) {
 func (p *PostgresDB) GetAgency(aid string) (*models.Agency, error) {
 	var dtoOrgao dto.AgencyDTO
 	aid = strings.ToLower(aid)
-	if err := p.db.Model(&dto.AgencyDTO{}).Where("id = ?", aid).First(&dtoOrgao).Error; err != nil {
-		return nil, fmt.Errorf("error getting agency '%s': %q", aid, err)
+	if err := p.reader().Model(&dto.AgencyDTO{}).Where("id = ?", aid).First(&dtoOrgao).Error; err != nil {
+		return nil, fmt.Errorf("error getting agency '%s': %w", aid, classify(err))
 	}
 	orgao, err := dtoOrgao.ConvertToModel()
 	if err != nil {
-		return nil, fmt.Errorf("error converting agency dto to model: %q", err)
+		return nil, fmt.Errorf("error converting agency dto to model: %w", err)
 	}
 	return orgao, nil
 }
 
 func (p *PostgresDB) GetAllAgencies() ([]models.Agency, error) {
 	var dtoOrgaos []dto.AgencyDTO
-	if err := p.db.Model(&dto.AgencyDTO{}).Find(&dtoOrgaos).Error; err != nil {
-		return nil, fmt.Errorf("error getting agencies: %q", err)
+	if err := p.reader().Model(&dto.AgencyDTO{}).Find(&dtoOrgaos).Error; err != nil {
+		return nil, fmt.Errorf("error getting agencies: %w", classify(err))
 	}
 	var orgaos []models.Agency
 	for _, dtoOrgao := range dtoOrgaos {
 		orgao, err := dtoOrgao.ConvertToModel()
 		if err != nil {
-			return nil, fmt.Errorf("error converting agency dto to model: %q", err)
+			return nil, fmt.Errorf("error converting agency dto to model: %w", err)
 		}
 		orgaos = append(orgaos, *orgao)
 	}
 	return orgaos, nil
 }
 
-func (p *PostgresDB) GetMonthlyInfo(agencies []models.Agency, year int) (map[string][]models.AgencyMonthlyInfo, error) {
-	var results = make(map[string][]models.AgencyMonthlyInfo)
-	//Mapeando os órgãos
-	for _, agency := range agencies {
-		var dtoAgmis []dto.AgencyMonthlyInfoDTO
-
-		mi := p.db.Model(&dto.AgencyMonthlyInfoDTO{}).Select("coletas.*, oma.inconsistente")
-		mi = mi.Joins(`LEFT JOIN orgao_mes_ano_inconsistentes oma 
-						ON oma.id_orgao = coletas.id_orgao 
-						AND oma.ano = coletas.ano 
-						AND oma.mes = coletas.mes`)
-		mi = mi.Where(`coletas.id_orgao = ? AND coletas.ano = ? 
-						AND coletas.atual = TRUE 
-						AND (coletas.procinfo::text = 'null' OR coletas.procinfo IS NULL)`, agency.ID, year)
-		mi = mi.Order("coletas.mes ASC")
-
-		if err := mi.Scan(&dtoAgmis).Error; err != nil {
-			return nil, fmt.Errorf("error getting monthly info: %q", err)
+// GetMonthlyInfo retorna as coletas atuais (e sem erro) dos órgãos no ano, agrupadas por
+// órgão e ordenadas por mês. Os dados de todos os órgãos são buscados em uma única consulta.
+// Quando informados, apenas os meses dentro de algum dos intervalos são retornados.
+func (p *PostgresDB) GetMonthlyInfo(agencies []models.Agency, year int, months ...models.MonthRange) (map[string][]models.AgencyMonthlyInfo, error) {
+	var ranges *gorm.DB
+	for _, m := range months {
+		if m.From < 1 || m.To > 12 || m.From > m.To {
+			return nil, models.NewError(models.ErrInvalidInput, fmt.Errorf("invalid month range: %d-%d", m.From, m.To))
 		}
-
-		//Convertendo os DTO's para modelos
-		for _, dtoAgmi := range dtoAgmis {
-			agmi, err := dtoAgmi.ConvertToModel()
-			if err != nil {
-				return nil, fmt.Errorf("error converting dto to model: %q", err)
-			}
-			results[agency.ID] = append(results[agency.ID], *agmi)
+		if ranges == nil {
+			ranges = p.db.Where("coletas.mes BETWEEN ? AND ?", m.From, m.To)
+		} else {
+			ranges = ranges.Or("coletas.mes BETWEEN ? AND ?", m.From, m.To)
 		}
 	}
-	return results, nil
+	return p.getMonthlyInfo(agencies, func(db *gorm.DB) *gorm.DB {
+		db = db.Where("coletas.ano = ?", year)
+		if ranges != nil {
+			db = db.Where(ranges)
+		}
+		return db
+	})
 }
 
-// Consultamos os nomes das rubricas que estão no sumário
-// Formatamos a query para que ela retorne o SQL necessário
-// Juntamos tudo na query principal
-func (p *PostgresDB) getItemSummary() (*string, error) {
-	queryRubricas := `SELECT 
-							string_agg(
-								format(
-									'SUM(CAST(sumario -> ''resumo_rubricas'' ->> %L AS DECIMAL)) AS %I',
-									chave, chave
-								),
-								', ' || E'\n'
-							) AS sql
-						FROM (
-							SELECT DISTINCT jsonb_object_keys((sumario -> 'resumo_rubricas')::jsonb) AS chave
-							FROM coletas WHERE atual = true
-						) sub;`
-
-	var resultRubricas *string
+// GetMonthlyInfoInPeriod retorna as coletas atuais (e sem erro) dos órgãos no período,
+// agrupadas por órgão e ordenadas por ano e mês.
+func (p *PostgresDB) GetMonthlyInfoInPeriod(agencies []models.Agency, period models.Period) (map[string][]models.AgencyMonthlyInfo, error) {
+	if err := period.Validate(); err != nil {
+		return nil, err
+	}
+	return p.getMonthlyInfo(agencies, func(db *gorm.DB) *gorm.DB {
+		return inPeriod(db, "coletas", period)
+	})
+}
 
-	result := p.db.Raw(queryRubricas)
-	if err := result.Scan(&resultRubricas).Error; err != nil {
-		return nil, fmt.Errorf("error getting sql: %w", err)
+func (p *PostgresDB) getMonthlyInfo(agencies []models.Agency, filter func(*gorm.DB) *gorm.DB) (map[string][]models.AgencyMonthlyInfo, error) {
+	var results = make(map[string][]models.AgencyMonthlyInfo)
+	if len(agencies) == 0 {
+		return results, nil
+	}
+	ids := make([]string, 0, len(agencies))
+	for _, agency := range agencies {
+		ids = append(ids, agency.ID)
 	}
 
-	return resultRubricas, nil
-}
+	mi := p.reader().Model(&dto.AgencyMonthlyInfoDTO{}).Select("coletas.*, oma.inconsistente")
+	mi = mi.Joins(`LEFT JOIN orgao_mes_ano_inconsistentes oma 
+					ON oma.id_orgao = coletas.id_orgao 
+					AND oma.ano = coletas.ano 
+					AND oma.mes = coletas.mes`)
+	mi = mi.Where(`coletas.id_orgao IN ? 
+					AND coletas.atual = TRUE 
+					AND (coletas.procinfo::text = 'null' OR coletas.procinfo IS NULL)`, ids)
+	mi = filter(mi)
+	mi = mi.Order("coletas.id_orgao, coletas.ano, coletas.mes ASC")
+
+	var dtoAgmis []dto.AgencyMonthlyInfoDTO
+	if err := mi.Scan(&dtoAgmis).Error; err != nil {
+		return nil, fmt.Errorf("error getting monthly info: %w", classify(err))
+	}
 
-// getColumnName extrai o nome da coluna de uma tag GORM
-func getColumnName(gormTag string) string {
-	parts := strings.Split(gormTag, ";")
-	for _, part := range parts {
-		if strings.HasPrefix(part, "column:") {
-			return strings.TrimPrefix(part, "column:")
+	//Convertendo os DTO's para modelos
+	for _, dtoAgmi := range dtoAgmis {
+		agmi, err := dtoAgmi.ConvertToModel()
+		if err != nil {
+			return nil, fmt.Errorf("error converting dto to model: %w", err)
 		}
+		results[agmi.AgencyID] = append(results[agmi.AgencyID], *agmi)
 	}
-	return ""
+	return results, nil
 }
 
-// Pegando as tags do DTO
-// e criando um mapa com os nomes das colunas predefinidas
-// a fim de não sobrescrever os valores e pegar apenas as rubricas
-// que não estão no DTO
-func getDtoTags(dto interface{}) map[string]interface{} {
-	t := reflect.TypeOf(dto)
-	var tags []string
-
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		gormTag := field.Tag.Get("gorm")
-		tags = append(tags, getColumnName(gormTag))
-	}
+// inPeriod filtra as linhas da tabela (que deve ter as colunas ano e mes) pelo período.
+func inPeriod(db *gorm.DB, table string, period models.Period) *gorm.DB {
+	cond := fmt.Sprintf("(%[1]s.ano, %[1]s.mes) >= (?, ?) AND (%[1]s.ano, %[1]s.mes) <= (?, ?)", table)
+	return db.Where(cond, period.FromYear, period.FromMonth, period.ToYear, period.ToMonth)
+}
 
-	dtoTags := make(map[string]interface{})
+// itemSummaries são os totais das rubricas agrupados por ano ou mês.
+type itemSummaries struct {
+	totals map[int]models.ItemSummary
+	items  []string // Todas as rubricas conhecidas
+}
 
-	for _, tag := range tags {
-		dtoTags[tag] = struct{}{}
+// get retorna o resumo de rubricas de um grupo. Todas as rubricas conhecidas estão
+// presentes no resumo, com valor 0 quando não há dados no grupo.
+func (s itemSummaries) get(group int) models.ItemSummary {
+	summary := make(models.ItemSummary, len(s.items))
+	for _, item := range s.items {
+		summary[item] = 0
 	}
+	for item, value := range s.totals[group] {
+		summary[item] = value
+	}
+	return summary
+}
 
-	return dtoTags
+// getItemSummaries soma os valores das rubricas das coletas atuais (e sem erro),
+// agrupando pela expressão groupBy (ex.: ano ou mês). O valor somado é dado pela expressão
+// value (ex.: o valor nominal, resumo_rubricas.valor, ou o valor deflacionado).
+func (p *PostgresDB) getItemSummaries(groupBy, value string, filter func(*gorm.DB) *gorm.DB) (*itemSummaries, error) {
+	db := p.reader()
+	var totals []dto.GroupedItemTotalDTO
+	m := db.Model(&dto.ItemSummaryDTO{}).Select(fmt.Sprintf("%s AS grupo, resumo_rubricas.rubrica, SUM(%s) AS valor", groupBy, value))
+	m = m.Joins(`JOIN coletas ON coletas.id_orgao = resumo_rubricas.id_orgao
+				 AND coletas.mes = resumo_rubricas.mes
+				 AND coletas.ano = resumo_rubricas.ano
+				 AND coletas.atual = TRUE
+				 AND (coletas.procinfo IS NULL OR coletas.procinfo::text = 'null')`)
+	m = filter(m).Group("grupo, resumo_rubricas.rubrica")
+	if err := m.Scan(&totals).Error; err != nil {
+		return nil, fmt.Errorf("error getting item totals: %w", classify(err))
+	}
+
+	summaries := &itemSummaries{totals: make(map[int]models.ItemSummary)}
+	if err := db.Model(&dto.ItemSummaryDTO{}).Distinct("rubrica").Order("rubrica").Pluck("rubrica", &summaries.items).Error; err != nil {
+		return nil, fmt.Errorf("error getting items: %w", classify(err))
+	}
+	for _, t := range totals {
+		if _, ok := summaries.totals[t.Group]; !ok {
+			summaries.totals[t.Group] = make(models.ItemSummary)
+		}
+		summaries.totals[t.Group][t.Item] = t.Value
+	}
+	return summaries, nil
 }
 
-func (p *PostgresDB) GetAnnualSummary(agency string) ([]models.AnnualSummary, error) {
+func (p *PostgresDB) GetAnnualSummary(agency string, opts ...models.AggregationOpts) ([]models.AnnualSummary, error) {
 	var dtoAmis []dto.AnnualSummaryDTO
 	agency = strings.ToLower(agency)
-
-	resultRubricas, err := p.getItemSummary()
+	d, err := p.newDeflation(opts)
 	if err != nil {
-		return nil, fmt.Errorf("error getting item summary: %w", err)
+		return nil, err
 	}
-
-	// Checa se o resultado é nulo ou não
-	// Se for nulo, inicializa com uma string vazia
-	// Se não for nulo, adiciona uma vírgula no final
-	// para que a query funcione corretamente
-	if resultRubricas == nil {
-		empty := ""
-		resultRubricas = &empty
-	} else if !strings.HasSuffix(*resultRubricas, ",") {
-		*resultRubricas += ","
+	current := func(db *gorm.DB) *gorm.DB {
+		return db.Where("coletas.id_orgao = ? AND atual = TRUE AND (procinfo::text = 'null' OR procinfo IS NULL) ", agency)
+	}
+	if err := d.check(current(p.reader().Model(&dto.AgencyMonthlyInfoDTO{})), "coletas"); err != nil {
+		return nil, err
 	}
 
+	f := d.factor("coletas")
 	query := fmt.Sprintf(`
 		coletas.ano,
 		coletas.id_orgao,
 		TRUNC(AVG((sumario -> 'membros')::text::int)) AS media_num_membros,
 		SUM((sumario -> 'membros')::text::int) AS total_num_membros,
-		SUM(CAST(sumario -> 'remuneracao_base' ->> 'total' AS DECIMAL)) AS remuneracao_base,
-		SUM(CAST(sumario -> 'outras_remuneracoes' ->> 'total' AS DECIMAL)) AS outras_remuneracoes,
-		SUM(CAST(sumario -> 'descontos' ->> 'total' AS DECIMAL)) AS descontos,
-		SUM(CAST(sumario -> 'remuneracoes' ->> 'total' AS DECIMAL)) AS remuneracoes,
-		%s
+		SUM(CAST(sumario -> 'remuneracao_base' ->> 'total' AS DECIMAL) * %[1]s) AS remuneracao_base,
+		SUM(CAST(sumario -> 'outras_remuneracoes' ->> 'total' AS DECIMAL) * %[1]s) AS outras_remuneracoes,
+		SUM(CAST(sumario -> 'descontos' ->> 'total' AS DECIMAL) * %[1]s) AS descontos,
+		SUM(CAST(sumario -> 'remuneracoes' ->> 'total' AS DECIMAL) * %[1]s) AS remuneracoes,
 		COUNT(*) AS meses_com_dados,
 		MAX(mpm.salario) AS remuneracao_base_membro,
 		MAX(mpm.beneficios) AS outras_remuneracoes_membro,
 		MAX(mpm.descontos) AS descontos_membro,
 		MAX(mpm.remuneracao) AS remuneracoes_membro,
-		oa.inconsistente`, *resultRubricas)
+		oa.inconsistente`, f)
 
-	join := `LEFT JOIN media_por_membro mpm ON coletas.ano = mpm.ano AND coletas.id_orgao = mpm.orgao
+	// Com deflação, as médias por membro são calculadas a partir dos contracheques, em vez
+	// de lidas da view materializada.
+	perMember := "media_por_membro"
+	var joinArgs []interface{}
+	if d != nil {
+		perMember = "(" + d.perMemberAverages("c.orgao = ?") + ")"
+		joinArgs = append(joinArgs, agency)
+	}
+	join := `LEFT JOIN ` + perMember + ` mpm ON coletas.ano = mpm.ano AND coletas.id_orgao = mpm.orgao
 			 LEFT JOIN orgao_ano_inconsistentes oa ON coletas.id_orgao = oa.id_orgao AND coletas.ano = oa.ano`
-	m := p.db.Model(&dto.AgencyMonthlyInfoDTO{}).Select(query).Joins(join)
-	m = m.Where("coletas.id_orgao = ? AND atual = TRUE AND (procinfo::text = 'null' OR procinfo IS NULL) ", agency)
+	m := p.reader().Model(&dto.AgencyMonthlyInfoDTO{}).Select(query).Joins(join, joinArgs...)
+	m = current(m)
 	m = m.Group("coletas.ano, coletas.id_orgao, oa.inconsistente").Order("coletas.ano ASC")
 	if err := m.Scan(&dtoAmis).Error; err != nil {
-		return nil, fmt.Errorf("error getting annual monthly info: %q", err)
-	}
-
-	// Pegando as tags do DTO
-	// e criando um mapa com os nomes das colunas predefinidas
-	// a fim de não sobrescrever os valores e pegar apenas as rubricas
-	// que não estão no DTO
-	dtoTags := getDtoTags(dto.AnnualSummaryDTO{})
-
-	// Pegando os nomes das colunas do resultado da query
-	// que inclui os nomes das rubricas
-	rows, err := m.Rows()
-	if err != nil {
-		return nil, fmt.Errorf("error executing query: %w", err)
+		return nil, fmt.Errorf("error getting annual monthly info: %w", classify(err))
 	}
-	defer rows.Close()
 
-	columns, err := rows.Columns()
+	// Os totais das rubricas são lidos da tabela normalizada 'resumo_rubricas'.
+	itemSummaries, err := p.getItemSummaries("resumo_rubricas.ano", "resumo_rubricas.valor * "+d.factor("resumo_rubricas"), func(db *gorm.DB) *gorm.DB {
+		return db.Where("resumo_rubricas.id_orgao = ?", agency)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error getting column names: %w", err)
-	}
-
-	// Iterando sobre as colunas e criando um slice de valores
-	// Assim, podemos pegar o valor pelo nome da coluna
-	values := make([]interface{}, len(columns))
-	valuePtrs := make([]interface{}, len(columns))
-
-	for i := range columns {
-		valuePtrs[i] = &values[i]
-	}
-
-	rubricasPorAno := make(map[int]map[string]float64)
-
-	for rows.Next() {
-		rows.Scan(valuePtrs...)
-
-		var ano int
-
-		// Checa se o valor é de um field predefinido (não rubrica)
-		// a partir de dtoTags
-		// Se não for, adiciona no mapa de rubricas
-		itemSummary := make(map[string]float64)
-		for i, col := range columns {
-			val := values[i]
-			if _, ok := dtoTags[col]; !ok && col != "id_orgao" {
-				if val != nil {
-					itemSummary[col], _ = strconv.ParseFloat(string(val.([]byte)), 64)
-				} else {
-					itemSummary[col] = 0
-				}
-			} else if col == "ano" {
-				ano = int(val.(int64))
-			}
-		}
-
-		// Adiciona o itemSummary no mapa de rubricas
-		// no respectivo ano
-		rubricasPorAno[ano] = itemSummary
-	}
-
-	for i := range dtoAmis {
-		ano := dtoAmis[i].Year
-		if itemSummary, ok := rubricasPorAno[ano]; ok {
-			dtoAmis[i].ItemSummary = itemSummary
-		}
+		return nil, fmt.Errorf("error getting item summary: %w", classify(err))
 	}
 
 	var amis []models.AnnualSummary
 	for _, dtoAmi := range dtoAmis {
+		dtoAmi.ItemSummary = itemSummaries.get(dtoAmi.Year)
 		amis = append(amis, *dtoAmi.ConvertToModel())
 	}
 	return amis, nil
@@ -501,7 +955,7 @@ func (p *PostgresDB) GetAnnualSummary(agency string) ([]models.AnnualSummary, er
 func (p *PostgresDB) GetOMA(month int, year int, agency string) (*models.AgencyMonthlyInfo, *models.Agency, error) {
 	var dtoAgmi dto.AgencyMonthlyInfoDTO
 	id := fmt.Sprintf("%s/%s/%d", strings.ToLower(agency), dto.AddZeroes(month), year)
-	m := p.db.Model(dto.AgencyMonthlyInfoDTO{}).Select(`coletas.*, oma.inconsistente`)
+	m := p.reader().Model(dto.AgencyMonthlyInfoDTO{}).Select(`coletas.*, oma.inconsistente`)
 	m = m.Joins(`LEFT JOIN orgao_mes_ano_inconsistentes oma
 				 ON oma.id_orgao = coletas.id_orgao
 				 AND oma.ano = coletas.ano
@@ -510,123 +964,80 @@ func (p *PostgresDB) GetOMA(month int, year int, agency string) (*models.AgencyM
 	m = m.Where("id = ? AND atual = true", id).First(&dtoAgmi)
 	if err := m.Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, nil, fmt.Errorf("there is no data with this parameters")
+			return nil, nil, models.NewError(models.ErrNotFound, fmt.Errorf("there is no data with this parameters"))
 		}
-		return nil, nil, fmt.Errorf("error getting 'coletas' with id (%s): %q", id, err)
+		return nil, nil, fmt.Errorf("error getting 'coletas' with id (%s): %w", id, classify(err))
 	}
 	agmi, err := dtoAgmi.ConvertToModel()
 	if err != nil {
-		return nil, nil, fmt.Errorf("error converting agmi dto to model: %q", err)
+		return nil, nil, fmt.Errorf("error converting agmi dto to model: %w", err)
 	}
 	agencyObject, err := p.GetAgency(agency)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error getting 'orgaos' with id (%s): %q", agency, err)
+		return nil, nil, fmt.Errorf("error getting 'orgaos' with id (%s): %w", agency, classify(err))
 	}
 	return agmi, agencyObject, nil
 }
 
 func (p *PostgresDB) GetGeneralMonthlyInfosFromYear(year int) ([]models.GeneralMonthlyInfo, error) {
-	var dtoAgmi dto.AgencyMonthlyInfoDTO
-	var dtoGmi []dto.GeneralMonthlyInfoDTO
+	return p.getGeneralMonthlyInfos(nil, func(db *gorm.DB, table string) *gorm.DB {
+		return db.Where(table+".ano = ?", year)
+	})
+}
 
-	resultRubricas, err := p.getItemSummary()
+// GetGeneralMonthlyInfosInPeriod retorna os totais mensais de todos os órgãos no período,
+// ordenados por ano e mês.
+func (p *PostgresDB) GetGeneralMonthlyInfosInPeriod(period models.Period, opts ...models.AggregationOpts) ([]models.GeneralMonthlyInfo, error) {
+	if err := period.Validate(); err != nil {
+		return nil, err
+	}
+	d, err := p.newDeflation(opts)
 	if err != nil {
-		return nil, fmt.Errorf("error getting item summary: %w", err)
+		return nil, err
 	}
+	return p.getGeneralMonthlyInfos(d, func(db *gorm.DB, table string) *gorm.DB {
+		return inPeriod(db, table, period)
+	})
+}
+
+func (p *PostgresDB) getGeneralMonthlyInfos(d *deflation, filter func(db *gorm.DB, table string) *gorm.DB) ([]models.GeneralMonthlyInfo, error) {
+	var dtoAgmi dto.AgencyMonthlyInfoDTO
+	var dtoGmi []dto.GeneralMonthlyInfoDTO
 
-	// Checa se o resultado é nulo ou não
-	// Se for nulo, inicializa com uma string vazia
-	// Se não for nulo, adiciona uma vírgula no início
-	// para que a query funcione corretamente
-	if resultRubricas == nil {
-		empty := ""
-		resultRubricas = &empty
-	} else if !strings.HasPrefix(*resultRubricas, ", ") {
-		*resultRubricas = ", " + *resultRubricas
+	current := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("atual=true AND (procinfo IS NULL OR procinfo::text = 'null')")
+		return filter(db, "coletas")
+	}
+	if err := d.check(current(p.reader().Model(&dtoAgmi)), "coletas"); err != nil {
+		return nil, err
 	}
 
 	query := fmt.Sprintf(`
+		ano,
 		mes,
 		SUM((sumario -> 'membros')::text::int) AS num_membros,
-		SUM(CAST(sumario -> 'remuneracao_base' ->> 'total' AS DECIMAL)) AS remuneracao_base,
-		SUM(CAST(sumario -> 'outras_remuneracoes' ->> 'total' AS DECIMAL)) AS outras_remuneracoes,
-		SUM(CAST(sumario -> 'descontos' ->> 'total' AS DECIMAL)) AS descontos,
-		SUM(CAST(sumario -> 'remuneracoes' ->> 'total' AS DECIMAL)) AS remuneracoes
-		%s`, *resultRubricas)
-
-	m := p.db.Model(&dtoAgmi).Select(query)
-	m = m.Where("ano = ? AND atual=true AND (procinfo IS NULL OR procinfo::text = 'null')", year)
-	m = m.Group("mes").Order("mes ASC")
-	if err := m.Scan(&dtoGmi).Error; err != nil {
-		return nil, fmt.Errorf("error getting general remuneration value: %q", err)
-	}
+		SUM(CAST(sumario -> 'remuneracao_base' ->> 'total' AS DECIMAL) * %[1]s) AS remuneracao_base,
+		SUM(CAST(sumario -> 'outras_remuneracoes' ->> 'total' AS DECIMAL) * %[1]s) AS outras_remuneracoes,
+		SUM(CAST(sumario -> 'descontos' ->> 'total' AS DECIMAL) * %[1]s) AS descontos,
+		SUM(CAST(sumario -> 'remuneracoes' ->> 'total' AS DECIMAL) * %[1]s) AS remuneracoes`, d.factor("coletas"))
 
-	// Pegando as tags do DTO
-	// e criando um mapa com os nomes das colunas predefinidas
-	// a fim de não sobrescrever os valores e pegar apenas as rubricas
-	// que não estão no DTO
-	dtoTags := getDtoTags(dto.GeneralMonthlyInfoDTO{})
-
-	// Pegando os nomes das colunas do resultado da query
-	// que inclui os nomes das rubricas
-	rows, err := m.Rows()
-	if err != nil {
-		return nil, fmt.Errorf("error executing query: %w", err)
+	m := current(p.reader().Model(&dtoAgmi).Select(query))
+	m = m.Group("ano, mes").Order("ano, mes ASC")
+	if err := m.Scan(&dtoGmi).Error; err != nil {
+		return nil, fmt.Errorf("error getting general remuneration value: %w", classify(err))
 	}
-	defer rows.Close()
 
-	columns, err := rows.Columns()
+	// Os totais das rubricas são lidos da tabela normalizada 'resumo_rubricas'.
+	itemSummaries, err := p.getItemSummaries("resumo_rubricas.ano * 100 + resumo_rubricas.mes", "resumo_rubricas.valor * "+d.factor("resumo_rubricas"), func(db *gorm.DB) *gorm.DB {
+		return filter(db, "resumo_rubricas")
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error getting column names: %w", err)
-	}
-
-	// Iterando sobre as colunas e criando um slice de valores
-	// Assim, podemos pegar o valor pelo nome da coluna
-	values := make([]interface{}, len(columns))
-	valuePtrs := make([]interface{}, len(columns))
-
-	for i := range columns {
-		valuePtrs[i] = &values[i]
-	}
-
-	rubricasPorMes := make(map[int]map[string]float64)
-
-	for rows.Next() {
-		rows.Scan(valuePtrs...)
-
-		var mes int
-
-		// Checa se o valor é de um field predefinido (não rubrica)
-		// a partir de dtoTags
-		// Se não for, adiciona no mapa de rubricas
-		itemSummary := make(map[string]float64)
-		for i, col := range columns {
-			val := values[i]
-			if _, ok := dtoTags[col]; !ok && col != "id_orgao" {
-				if val != nil {
-					itemSummary[col], _ = strconv.ParseFloat(string(val.([]byte)), 64)
-				} else {
-					itemSummary[col] = 0
-				}
-			} else if col == "mes" {
-				mes = int(val.(int64))
-			}
-		}
-
-		// Adiciona o itemSummary no mapa de rubricas
-		// no respectivo mes
-		rubricasPorMes[mes] = itemSummary
-	}
-
-	for i := range dtoGmi {
-		mes := dtoGmi[i].Month
-		if itemSummary, ok := rubricasPorMes[mes]; ok {
-			dtoGmi[i].ItemSummary = itemSummary
-		}
+		return nil, fmt.Errorf("error getting item summary: %w", classify(err))
 	}
 
 	var gmis []models.GeneralMonthlyInfo
 	for _, gmi := range dtoGmi {
+		gmi.ItemSummary = itemSummaries.get(gmi.Year*100 + gmi.Month)
 		gmis = append(gmis, *gmi.ConvertToModel())
 	}
 	return gmis, nil
@@ -635,11 +1046,11 @@ func (p *PostgresDB) GetGeneralMonthlyInfosFromYear(year int) ([]models.GeneralM
 func (p *PostgresDB) GetFirstDateWithMonthlyInfo() (int, int, error) {
 	var dtoAgmi dto.AgencyMonthlyInfoDTO
 	var year, month int
-	m := p.db.Model(&dtoAgmi).Select("MIN(ano), MIN(mes)")
+	m := p.reader().Model(&dtoAgmi).Select("MIN(ano), MIN(mes)")
 	m = m.Where("atual=true AND (procinfo IS NULL OR procinfo::text = 'null')")
 	m = m.Where("ano = (SELECT min(ano) FROM coletas)")
 	if err := m.Row().Scan(&year, &month); err != nil {
-		return 0, 0, fmt.Errorf("error getting first date with monthly info: %q", err)
+		return 0, 0, fmt.Errorf("error getting first date with monthly info: %w", classify(err))
 	}
 	return month, year, nil
 }
@@ -647,11 +1058,11 @@ func (p *PostgresDB) GetFirstDateWithMonthlyInfo() (int, int, error) {
 func (p *PostgresDB) GetLastDateWithMonthlyInfo() (int, int, error) {
 	var dtoAgmi dto.AgencyMonthlyInfoDTO
 	var year, month int
-	m := p.db.Model(&dtoAgmi).Select("MAX(ano),MAX(mes)")
+	m := p.reader().Model(&dtoAgmi).Select("MAX(ano),MAX(mes)")
 	m = m.Where("atual=true AND (procinfo IS NULL OR procinfo::text='null')")
 	m = m.Where("ano = (SELECT MAX(ano) FROM coletas)")
 	if err := m.Row().Scan(&year, &month); err != nil {
-		return 0, 0, fmt.Errorf("error getting last date with monthly info: %q", err)
+		return 0, 0, fmt.Errorf("error getting last date with monthly info: %w", classify(err))
 	}
 	return month, year, nil
 }
@@ -667,10 +1078,10 @@ func (p *PostgresDB) GetGeneralMonthlyInfo() (float64, error) {
 			), 0
 		)
 		`
-	m := p.db.Model(&dtoAgmi).Select(query)
+	m := p.reader().Model(&dtoAgmi).Select(query)
 	m = m.Where("atual=true AND (procinfo IS NULL OR procinfo::text = 'null')")
 	if err := m.Scan(&value).Error; err != nil {
-		return 0, fmt.Errorf("error getting general remuneration value: %q", err)
+		return 0, fmt.Errorf("error getting general remuneration value: %w", classify(err))
 	}
 	return value, nil
 }
@@ -711,9 +1122,9 @@ func (p *PostgresDB) GetIndexInformation(name string, month, year int) (map[stri
 			params = append(params, name)
 		}
 	}
-	d = p.db.Model(&dtoIndex).Select("coletas.*, orgaos.jurisdicao as jurisdicao").Joins(query, params...)
+	d = p.reader().Model(&dtoIndex).Select("coletas.*, orgaos.jurisdicao as jurisdicao").Joins(query, params...)
 	if err := d.Scan(&dtoIndex).Error; err != nil {
-		return nil, fmt.Errorf("error getting all indexes: %w", err)
+		return nil, fmt.Errorf("error getting all indexes: %w", classify(err))
 	}
 	// Agrupando os índices por órgão
 	indexes := make(map[string][]models.IndexInformation)
@@ -727,18 +1138,18 @@ func (p *PostgresDB) GetIndexInformation(name string, month, year int) (map[stri
 func (p *PostgresDB) GetAllAgencyCollection(agency string) ([]models.AgencyMonthlyInfo, error) {
 	var dtoAgmis []dto.AgencyMonthlyInfoDTO
 	//Pegando todas as coletas atuais de um determinado órgão.
-	m := p.db.Model(&dto.AgencyMonthlyInfoDTO{})
+	m := p.reader().Model(&dto.AgencyMonthlyInfoDTO{})
 	m = m.Where("id_orgao = ? AND atual = TRUE", agency)
 	m = m.Order("(ano, mes) ASC")
 	if err := m.Find(&dtoAgmis).Error; err != nil {
-		return nil, fmt.Errorf("error getting all agency collections: %q", err)
+		return nil, fmt.Errorf("error getting all agency collections: %w", classify(err))
 	}
 
 	var collections []models.AgencyMonthlyInfo
 	for _, dtoAgmi := range dtoAgmis {
 		agmi, err := dtoAgmi.ConvertToModel()
 		if err != nil {
-			return nil, fmt.Errorf("error converting dto to model: %q", err)
+			return nil, fmt.Errorf("error converting dto to model: %w", err)
 		}
 		agmi.Score.EasinessScore = calcEasinessScore(agency, agmi.Score.EasinessScore)
 		collections = append(collections, *agmi)
@@ -749,6 +1160,7 @@ func (p *PostgresDB) GetAllAgencyCollection(agency string) ([]models.AgencyMonth
 // Verificamos se o órgão pertence ao painel do CNJ (ou se é um ministério público)
 // O índice de facilidade para os órgãos do CNJ é padronizado, mesmo quando não há dados para o mês.
 // obs.: o "STF" é o único tribunal que monitoramos e que não pertence ao CNJ
+// A mesma regra é aplicada nas consultas SQL por easinessScoreSQL.
 func calcEasinessScore(agency string, easinessScore float64) float64 {
 	if !strings.Contains(strings.ToLower(agency), "mp") && agency != "stf" {
 		return 0.5
@@ -757,15 +1169,31 @@ func calcEasinessScore(agency string, easinessScore float64) float64 {
 	}
 }
 
+// easinessScoreSQL é a expressão SQL equivalente a calcEasinessScore para a coleta de alias table.
+func easinessScoreSQL(table string) string {
+	return fmt.Sprintf("CASE WHEN LOWER(%[1]s.id_orgao) NOT LIKE '%%mp%%' AND %[1]s.id_orgao <> 'stf' THEN 0.5 ELSE %[1]s.indice_facilidade END", table)
+}
+
 func (p *PostgresDB) GetPaychecks(agency models.Agency, year int) ([]models.Paycheck, error) {
+	//Pegando os contracheques do postgres, filtrando por órgão e ano
+	return p.getPaychecks(p.reader().Where("orgao = ? AND ano = ? ", agency.ID, year))
+}
+
+// GetPaychecksInPeriod retorna os contracheques do órgão no período, ordenados por ano, mês e id.
+func (p *PostgresDB) GetPaychecksInPeriod(agency models.Agency, period models.Period) ([]models.Paycheck, error) {
+	if err := period.Validate(); err != nil {
+		return nil, err
+	}
+	return p.getPaychecks(inPeriod(p.reader().Where("orgao = ?", agency.ID), "contracheques", period))
+}
+
+func (p *PostgresDB) getPaychecks(db *gorm.DB) ([]models.Paycheck, error) {
 	var results []models.Paycheck
 	var dtoPaychecks []dto.PaycheckDTO
-	//Pegando os contracheques do postgres, filtrando por órgão e ano
-	m := p.db.Model(&dto.PaycheckDTO{})
-	m = m.Where("orgao = ? AND ano = ? ", agency.ID, year)
-	m = m.Order("mes, id ASC")
+	m := db.Model(&dto.PaycheckDTO{})
+	m = m.Order("ano, mes, id ASC")
 	if err := m.Find(&dtoPaychecks).Error; err != nil {
-		return nil, fmt.Errorf("error getting paychecks: %q", err)
+		return nil, fmt.Errorf("error getting paychecks: %w", classify(err))
 	}
 	//Convertendo os DTO's para modelos
 	for _, dtoPaycheck := range dtoPaychecks {
@@ -776,14 +1204,26 @@ func (p *PostgresDB) GetPaychecks(agency models.Agency, year int) ([]models.Payc
 }
 
 func (p *PostgresDB) GetPaycheckItems(agency models.Agency, year int) ([]models.PaycheckItem, error) {
+	//Pegando as remuneracoes do postgres, filtrando por órgão e ano
+	return p.getPaycheckItems(p.reader().Where("orgao = ? AND ano = ?", agency.ID, year))
+}
+
+// GetPaycheckItemsInPeriod retorna as remunerações do órgão no período, ordenadas por ano, mês,
+// contracheque e id.
+func (p *PostgresDB) GetPaycheckItemsInPeriod(agency models.Agency, period models.Period) ([]models.PaycheckItem, error) {
+	if err := period.Validate(); err != nil {
+		return nil, err
+	}
+	return p.getPaycheckItems(inPeriod(p.reader().Where("orgao = ?", agency.ID), "remuneracoes", period))
+}
+
+func (p *PostgresDB) getPaycheckItems(db *gorm.DB) ([]models.PaycheckItem, error) {
 	var results []models.PaycheckItem
 	var dtoPaycheckItems []dto.PaycheckItemDTO
-	//Pegando as remuneracoes do postgres, filtrando por órgão e ano
-	m := p.db.Model(&dto.PaycheckItemDTO{})
-	m = m.Where("orgao = ? AND ano = ?", agency.ID, year)
-	m = m.Order("mes, id_contracheque, id ASC")
+	m := db.Model(&dto.PaycheckItemDTO{})
+	m = m.Order("ano, mes, id_contracheque, id ASC")
 	if err := m.Find(&dtoPaycheckItems).Error; err != nil {
-		return nil, fmt.Errorf("error getting paycheck items: %q", err)
+		return nil, fmt.Errorf("error getting paycheck items: %w", classify(err))
 	}
 	//Convertendo os DTO's para modelos
 	for _, dtoPaycheckItem := range dtoPaycheckItems {
@@ -793,17 +1233,35 @@ func (p *PostgresDB) GetPaycheckItems(agency models.Agency, year int) ([]models.
 	return results, nil
 }
 
-func (p *PostgresDB) GetAveragePerCapita(agency string, ano int) (*models.PerCapitaData, error) {
+func (p *PostgresDB) GetAveragePerCapita(agency string, ano int, opts ...models.AggregationOpts) (*models.PerCapitaData, error) {
+	d, err := p.newDeflation(opts)
+	if err != nil {
+		return nil, err
+	}
 	var dtoAvg dto.PerCapitaData
-	m := p.db.Model(&dto.PerCapitaData{})
-	m = m.Where("orgao = ? AND ano = ?", agency, ano)
+	m, err := p.perCapitaData(d, "orgao = ? AND ano = ?", agency, ano)
+	if err != nil {
+		return nil, err
+	}
 	if err := m.Find(&dtoAvg).Error; err != nil {
-		return nil, fmt.Errorf("error getting average per capita: %q", err)
+		return nil, fmt.Errorf("error getting average per capita: %w", classify(err))
 	}
 	avg := dtoAvg.ConvertToModel()
 	return avg, nil
 }
 
+// perCapitaData retorna a consulta das médias por membro que atendem à condição: a view
+// materializada 'media_por_membro' ou, com deflação, as médias calculadas a partir dos contracheques.
+func (p *PostgresDB) perCapitaData(d *deflation, where string, args ...interface{}) (*gorm.DB, error) {
+	if d == nil {
+		return p.reader().Model(&dto.PerCapitaData{}).Where(where, args...), nil
+	}
+	if err := d.check(p.reader().Table("contracheques").Where(where, args...), "contracheques"); err != nil {
+		return nil, err
+	}
+	return p.reader().Raw(d.perMemberAverages(where), args...), nil
+}
+
 func (p *PostgresDB) GetNotices(agency string, year int, month int) ([]*string, error) {
 	var notices []*string
 	params := []interface{}{}
@@ -813,7 +1271,7 @@ func (p *PostgresDB) GetNotices(agency string, year int, month int) ([]*string,
 	if agency != "" {
 		params = append(params, agency)
 	} else {
-		return nil, fmt.Errorf("error agency cannot be empty")
+		return nil, models.NewError(models.ErrInvalidInput, fmt.Errorf("error agency cannot be empty"))
 	}
 
 	if year != 0 {
@@ -825,9 +1283,9 @@ func (p *PostgresDB) GetNotices(agency string, year int, month int) ([]*string,
 		}
 	}
 
-	result := p.db.Model(&dto.AgencyMonthlyInfoDTO{}).Distinct("avisos").Where(query, params...)
+	result := p.reader().Model(&dto.AgencyMonthlyInfoDTO{}).Distinct("avisos").Where(query, params...)
 	if err := result.Find(&notices).Error; err != nil {
-		return nil, fmt.Errorf("error getting notices: %w", err)
+		return nil, fmt.Errorf("error getting notices: %w", classify(err))
 	}
 
 	return notices, nil
@@ -835,12 +1293,18 @@ func (p *PostgresDB) GetNotices(agency string, year int, month int) ([]*string,
 
 // GetAveragePerAgency( retorna os dados per capita para um determinado ano de cada órgão.
 // Isto é, salário, benefícios, descontos e remuneração médio por membro em um ano.
-func (p *PostgresDB) GetAveragePerAgency(year int) ([]models.PerCapitaData, error) {
+func (p *PostgresDB) GetAveragePerAgency(year int, opts ...models.AggregationOpts) ([]models.PerCapitaData, error) {
+	d, err := p.newDeflation(opts)
+	if err != nil {
+		return nil, err
+	}
 	var dtoPerCapitaData []dto.PerCapitaData
-	m := p.db.Model(&dto.PerCapitaData{})
-	m = m.Where("ano = ?", year)
+	m, err := p.perCapitaData(d, "ano = ?", year)
+	if err != nil {
+		return nil, err
+	}
 	if err := m.Find(&dtoPerCapitaData).Error; err != nil {
-		return nil, fmt.Errorf("error getting per capita data: %q", err)
+		return nil, fmt.Errorf("error getting per capita data: %w", classify(err))
 	}
 
 	var averagePerAgency []models.PerCapitaData
@@ -864,11 +1328,11 @@ func (p *PostgresDB) GetRetroactivePayments(agency models.Agency, year int, mont
 		params = append(params, month)
 	}
 	//Pegando os contracheques do postgres, filtrando por órgão e ano
-	m := p.db.Model(&dto.RetroactivePaymentsDTO{})
+	m := p.reader().Model(&dto.RetroactivePaymentsDTO{})
 	m = m.Where(query, params...)
 	m = m.Order("mes, id ASC")
 	if err := m.Find(&dtoRetroactivePayments).Error; err != nil {
-		return nil, fmt.Errorf("error getting retroactive payments: %q", err)
+		return nil, fmt.Errorf("error getting retroactive payments: %w", classify(err))
 	}
 	//Convertendo os DTO's para modelos
 	for _, dtoRetroactivePayment := range dtoRetroactivePayments {
@@ -877,3 +1341,229 @@ func (p *PostgresDB) GetRetroactivePayments(agency models.Agency, year int, mont
 	}
 	return results, nil
 }
+
+// DeleteMonthlyData remove todas as revisões da coleta de um órgão em um mês/ano e
+// os dados derivados dela. Tudo é feito em uma única transação: se uma das remoções
+// falhar, nada é removido. O relatório retornado contém a quantidade de linhas
+// removidas de cada tabela e as URLs dos arquivos relacionados (backups, pacotes e zips),
+// que podem ser removidos do file storage pelo chamador.
+func (p *PostgresDB) DeleteMonthlyData(agency string, month, year int) (*models.DeletionReport, error) {
+	defer p.markWrite()
+	agency = strings.ToLower(agency)
+	report := &models.DeletionReport{AgencyID: agency, Month: month, Year: year}
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		// Coletando as URLs dos arquivos de todas as revisões antes de removê-las.
+		var dtoAgmis []dto.AgencyMonthlyInfoDTO
+		id := fmt.Sprintf("%s/%s/%d", agency, dto.AddZeroes(month), year)
+		if err := tx.Model(dto.AgencyMonthlyInfoDTO{}).Where("id = ?", id).Find(&dtoAgmis).Error; err != nil {
+			return fmt.Errorf("error getting 'coletas' with id (%s): %w", id, classify(err))
+		}
+		files := make(map[string]struct{})
+		for _, dtoAgmi := range dtoAgmis {
+			agmi, err := dtoAgmi.ConvertToModel()
+			if err != nil {
+				return fmt.Errorf("error converting agmi dto to model: %w", err)
+			}
+			for _, bkp := range agmi.Backups {
+				files[bkp.URL] = struct{}{}
+			}
+			if agmi.Package != nil {
+				files[agmi.Package.URL] = struct{}{}
+			}
+		}
+		var dtoRemus []dto.RemunerationsDTO
+		if err := tx.Model(dto.RemunerationsDTO{}).Where("id_orgao = ? AND mes = ? AND ano = ?", agency, month, year).Find(&dtoRemus).Error; err != nil {
+			return fmt.Errorf("error getting 'remuneracoes_zips': %w", classify(err))
+		}
+		for _, dtoRemu := range dtoRemus {
+			files[dtoRemu.ZipUrl] = struct{}{}
+		}
+		// Pacotes mensais do órgão/mês (os anuais e de grupo continuam válidos para os demais meses).
+		monthlyPackages := tx.Where("escopo = ? AND id_orgao = ? AND mes = ? AND ano = ?", string(models.PackageScopeMonthly), agency, month, year)
+		var dtoPkgs []dto.PackageDTO
+		if err := monthlyPackages.Model(dto.PackageDTO{}).Find(&dtoPkgs).Error; err != nil {
+			return fmt.Errorf("error getting 'pacotes': %w", classify(err))
+		}
+		for _, dtoPkg := range dtoPkgs {
+			pkg, err := dtoPkg.ConvertToModel()
+			if err != nil {
+				return fmt.Errorf("error converting package dto to model: %w", err)
+			}
+			files[pkg.Package.URL] = struct{}{}
+		}
+		delete(files, "")
+		for f := range files {
+			report.Files = append(report.Files, f)
+		}
+		sort.Strings(report.Files)
+
+		// Removendo as remunerações antes dos contracheques para sabermos quantas foram removidas,
+		// já que a remoção dos contracheques as removeria em cascata.
+		res := tx.Where("orgao = ? AND mes = ? AND ano = ?", agency, month, year).Delete(&dto.PaycheckItemDTO{})
+		if res.Error != nil {
+			return fmt.Errorf("error deleting 'remuneracoes': %w", classify(res.Error))
+		}
+		report.PaycheckItems = res.RowsAffected
+
+		res = tx.Where("orgao = ? AND mes = ? AND ano = ?", agency, month, year).Delete(&dto.PaycheckDTO{})
+		if res.Error != nil {
+			return fmt.Errorf("error deleting 'contracheques': %w", classify(res.Error))
+		}
+		report.Paychecks = res.RowsAffected
+
+		res = tx.Where("orgao = ? AND mes = ? AND ano = ?", agency, month, year).Delete(&dto.RetroactivePaymentsDTO{})
+		if res.Error != nil {
+			return fmt.Errorf("error deleting 'retroativos': %w", classify(res.Error))
+		}
+		report.RetroactivePayments = res.RowsAffected
+
+		res = tx.Where("id_orgao = ? AND mes = ? AND ano = ?", agency, month, year).Delete(&dto.RemunerationsDTO{})
+		if res.Error != nil {
+			return fmt.Errorf("error deleting 'remuneracoes_zips': %w", classify(res.Error))
+		}
+		report.RemunerationZips = res.RowsAffected
+
+		if err := tx.Where("id_orgao = ? AND mes = ? AND ano = ?", agency, month, year).Delete(&dto.ItemSummaryDTO{}).Error; err != nil {
+			return fmt.Errorf("error deleting 'resumo_rubricas': %w", classify(err))
+		}
+
+		res = tx.Where("escopo = ? AND id_orgao = ? AND mes = ? AND ano = ?", string(models.PackageScopeMonthly), agency, month, year).Delete(&dto.PackageDTO{})
+		if res.Error != nil {
+			return fmt.Errorf("error deleting 'pacotes': %w", classify(res.Error))
+		}
+		report.Packages = res.RowsAffected
+
+		res = tx.Where("id_orgao = ? AND mes = ? AND ano = ?", agency, month, year).Delete(&dto.AnomalyDTO{})
+		if res.Error != nil {
+			return fmt.Errorf("error deleting 'anomalias': %w", classify(res.Error))
+		}
+		report.Anomalies = res.RowsAffected
+
+		res = tx.Where("id = ?", id).Delete(&dto.AgencyMonthlyInfoDTO{})
+		if res.Error != nil {
+			return fmt.Errorf("error deleting 'coletas': %w", classify(res.Error))
+		}
+		report.Collections = res.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error performing transaction: %w", classify(err))
+	}
+	return report, nil
+}
+
+func (p *PostgresDB) StorePackage(pkg models.Package) error {
+	defer p.markWrite()
+	if pkg.Key == "" {
+		return models.NewError(models.ErrInvalidInput, fmt.Errorf("package key cannot be empty"))
+	}
+	pacote, err := dto.NewPackageDTO(pkg)
+	if err != nil {
+		return models.NewError(models.ErrInvalidInput, fmt.Errorf("error converting package to dto: %w", err))
+	}
+	if err := p.db.Model(dto.PackageDTO{}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chave"}},
+		UpdateAll: true,
+	}).Create(pacote).Error; err != nil {
+		return fmt.Errorf("error inserting 'pacotes': %w", classify(err))
+	}
+	return nil
+}
+
+func (p *PostgresDB) GetPackages(opts models.PackageFilterOpts) ([]models.Package, error) {
+	var dtoPkgs []dto.PackageDTO
+	m := p.reader().Model(&dto.PackageDTO{})
+	if opts.AgencyID != nil {
+		m = m.Where("id_orgao = ?", strings.ToLower(*opts.AgencyID))
+	}
+	if opts.Month != nil {
+		m = m.Where("mes = ?", *opts.Month)
+	}
+	if opts.Year != nil {
+		m = m.Where("ano = ?", *opts.Year)
+	}
+	if opts.Group != nil {
+		m = m.Where("LOWER(grupo) = ?", strings.ToLower(*opts.Group))
+	}
+	if opts.Scope != nil {
+		m = m.Where("escopo = ?", string(*opts.Scope))
+	}
+	m = m.Order("escopo, id_orgao, grupo, ano, mes, chave ASC")
+	if err := m.Find(&dtoPkgs).Error; err != nil {
+		return nil, fmt.Errorf("error getting packages: %w", classify(err))
+	}
+
+	var pkgs []models.Package
+	for _, dtoPkg := range dtoPkgs {
+		pkg, err := dtoPkg.ConvertToModel()
+		if err != nil {
+			return nil, fmt.Errorf("error converting package dto to model: %w", err)
+		}
+		pkgs = append(pkgs, *pkg)
+	}
+	return pkgs, nil
+}
+
+// incomeHistogramBuckets são os limites superiores das faixas do histograma de renda.
+// Salários acima da última faixa são contabilizados na faixa incomeHistogramOverflow.
+var incomeHistogramBuckets = []int{10000, 20000, 30000, 40000, 50000}
+
+const incomeHistogramOverflow = -1
+
+func (p *PostgresDB) ComputeSummary(agency string, month, year int) (*models.Summary, error) {
+	db := p.reader()
+	where := "orgao = ? AND mes = ? AND ano = ?"
+	agency = strings.ToLower(agency)
+
+	var summary dto.SummaryDTO
+	if err := db.Model(&dto.PaycheckDTO{}).Select(`COUNT(*) AS membros,
+		COALESCE(MAX(salario), 0) AS salario_maximo, COALESCE(MIN(salario), 0) AS salario_minimo,
+		COALESCE(AVG(salario), 0) AS salario_media, COALESCE(SUM(salario), 0) AS salario_total,
+		COALESCE(MAX(beneficios), 0) AS beneficios_maximo, COALESCE(MIN(beneficios), 0) AS beneficios_minimo,
+		COALESCE(AVG(beneficios), 0) AS beneficios_media, COALESCE(SUM(beneficios), 0) AS beneficios_total,
+		COALESCE(MAX(descontos), 0) AS descontos_maximo, COALESCE(MIN(descontos), 0) AS descontos_minimo,
+		COALESCE(AVG(descontos), 0) AS descontos_media, COALESCE(SUM(descontos), 0) AS descontos_total,
+		COALESCE(MAX(remuneracao), 0) AS remuneracao_maximo, COALESCE(MIN(remuneracao), 0) AS remuneracao_minimo,
+		COALESCE(AVG(remuneracao), 0) AS remuneracao_media, COALESCE(SUM(remuneracao), 0) AS remuneracao_total,
+		percentile_cont(`+dto.PercentileFractions+`) WITHIN GROUP (ORDER BY salario) AS salario_percentis,
+		percentile_cont(`+dto.PercentileFractions+`) WITHIN GROUP (ORDER BY beneficios) AS beneficios_percentis,
+		percentile_cont(`+dto.PercentileFractions+`) WITHIN GROUP (ORDER BY descontos) AS descontos_percentis,
+		percentile_cont(`+dto.PercentileFractions+`) WITHIN GROUP (ORDER BY remuneracao) AS remuneracao_percentis`).
+		Where(where, agency, month, year).Scan(&summary).Error; err != nil {
+		return nil, fmt.Errorf("error computing summary: %w", classify(err))
+	}
+	if summary.Count == 0 {
+		return nil, models.NewError(models.ErrNotFound, fmt.Errorf("there are no paychecks for %s/%s/%d", agency, dto.AddZeroes(month), year))
+	}
+
+	// Faixa de cada salário: o menor limite superior maior ou igual ao salário, ou a faixa excedente.
+	bucket := "CASE"
+	for _, b := range incomeHistogramBuckets {
+		bucket += fmt.Sprintf(" WHEN salario <= %d THEN %d", b, b)
+	}
+	bucket += fmt.Sprintf(" ELSE %d END", incomeHistogramOverflow)
+	var histogram []dto.HistogramBucketDTO
+	if err := db.Model(&dto.PaycheckDTO{}).Select(bucket+" AS faixa, COUNT(*) AS quantidade").
+		Where(where, agency, month, year).Group("faixa").Scan(&histogram).Error; err != nil {
+		return nil, fmt.Errorf("error computing income histogram: %w", classify(err))
+	}
+	// O resumo de rubricas considera apenas as outras remunerações (R/O). Rubricas
+	// não identificadas (sem item sanitizado) são agregadas em 'outras'.
+	var items []dto.ItemTotalDTO
+	if err := db.Model(&dto.PaycheckItemDTO{}).Select("COALESCE(item_sanitizado, 'outras') AS rubrica, SUM(valor) AS valor").
+		Where(where+" AND tipo = 'R/O'", agency, month, year).Group("rubrica").Scan(&items).Error; err != nil {
+		return nil, fmt.Errorf("error computing item summary: %w", classify(err))
+	}
+
+	computed := summary.ConvertToModel(histogram, items)
+	// Todas as faixas estão presentes no histograma, mesmo que vazias.
+	for _, b := range incomeHistogramBuckets {
+		if _, ok := computed.IncomeHistogram[b]; !ok {
+			computed.IncomeHistogram[b] = 0
+		}
+	}
+	if _, ok := computed.IncomeHistogram[incomeHistogramOverflow]; !ok {
+		computed.IncomeHistogram[incomeHistogramOverflow] = 0
+	}
+	return computed, nil
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dadosjusbr/storage/models"
+	"github.com/dadosjusbr/storage/repo/database/dto"
+)
+
+// rankingSource retorna a consulta que calcula o valor da métrica para cada órgão (colunas
+// orgao e valor) no ano ou mês, e seus argumentos. Os índices são os mesmos retornados por
+// GetIndexInformation: o de facilidade é padronizado para os órgãos do CNJ (easinessScoreSQL)
+// e o de transparência é o armazenado na coleta. Os valores por membro de um mês consideram
+// todos os membros do sumário; os de um ano vêm da view 'media_por_membro', que considera
+// apenas os membros com mais de um mês de contracheques no ano.
+func rankingSource(opts models.RankingOpts) (string, []interface{}, error) {
+	if err := opts.Validate(); err != nil {
+		return "", nil, err
+	}
+	current := "c.atual = TRUE AND (c.procinfo IS NULL OR c.procinfo::text = 'null')"
+	if opts.Month != nil {
+		// Em um mês, os valores vêm do sumário e dos índices da coleta.
+		var value string
+		switch opts.Metric {
+		case models.RankByRemunerationPerCapita:
+			value = "CAST(c.sumario -> 'remuneracoes' ->> 'total' AS DECIMAL) / NULLIF((c.sumario -> 'membros')::text::int, 0)"
+		case models.RankByBenefitsShare:
+			// Totais zerados são omitidos do sumário.
+			value = `COALESCE(CAST(c.sumario -> 'outras_remuneracoes' ->> 'total' AS DECIMAL), 0) /
+				NULLIF(COALESCE(CAST(c.sumario -> 'remuneracao_base' ->> 'total' AS DECIMAL), 0) + COALESCE(CAST(c.sumario -> 'outras_remuneracoes' ->> 'total' AS DECIMAL), 0), 0)`
+		case models.RankByTransparencyIndex, models.RankByCompletenessIndex, models.RankByEaseOfAccessIndex:
+			value = indexColumn(opts.Metric)
+		default:
+			return "", nil, models.NewError(models.ErrInvalidInput, fmt.Errorf("invalid ranking metric: %q", opts.Metric))
+		}
+		return fmt.Sprintf("SELECT c.id_orgao AS orgao, %s AS valor FROM coletas c WHERE %s AND c.ano = ? AND c.mes = ?", value, current),
+			[]interface{}{opts.Year, *opts.Month}, nil
+	}
+	// Em um ano, os valores por membro vêm da view 'media_por_membro' e os índices são a média dos meses.
+	switch opts.Metric {
+	case models.RankByRemunerationPerCapita:
+		return "SELECT orgao, remuneracao AS valor FROM media_por_membro WHERE ano = ?", []interface{}{opts.Year}, nil
+	case models.RankByBenefitsShare:
+		return "SELECT orgao, beneficios / NULLIF(salario + beneficios, 0) AS valor FROM media_por_membro WHERE ano = ?", []interface{}{opts.Year}, nil
+	case models.RankByTransparencyIndex, models.RankByCompletenessIndex, models.RankByEaseOfAccessIndex:
+		return fmt.Sprintf("SELECT c.id_orgao AS orgao, AVG(%s) AS valor FROM coletas c WHERE %s AND c.ano = ? GROUP BY c.id_orgao", indexColumn(opts.Metric), current),
+			[]interface{}{opts.Year}, nil
+	}
+	return "", nil, models.NewError(models.ErrInvalidInput, fmt.Errorf("invalid ranking metric: %q", opts.Metric))
+}
+
+// indexColumn retorna a expressão do índice da coleta c usado pela métrica.
+func indexColumn(metric models.RankingMetric) string {
+	if metric == models.RankByEaseOfAccessIndex {
+		return easinessScoreSQL("c")
+	}
+	return "c." + string(metric)
+}
+
+// GetRanking ordena os órgãos pela métrica, do maior para o menor valor, no ano ou mês. Os
+// filtros de jurisdição e UF são aplicados antes da ordenação, ou seja, as posições e os
+// percentis são relativos aos órgãos filtrados. Órgãos sem valor para a métrica são ignorados.
+func (p *PostgresDB) GetRanking(opts models.RankingOpts) ([]models.RankingEntry, error) {
+	source, args, err := rankingSource(opts)
+	if err != nil {
+		return nil, err
+	}
+	where := []string{"r.valor IS NOT NULL"}
+	if opts.Jurisdiction != nil {
+		where = append(where, "LOWER(o.jurisdicao) = ?")
+		args = append(args, strings.ToLower(*opts.Jurisdiction))
+	}
+	if opts.UF != nil {
+		where = append(where, "UPPER(o.uf) = ?")
+		args = append(args, strings.ToUpper(*opts.UF))
+	}
+	query := fmt.Sprintf(`
+		SELECT r.orgao, o.nome, r.valor,
+			RANK() OVER (ORDER BY r.valor DESC) AS posicao,
+			PERCENT_RANK() OVER (ORDER BY r.valor) * 100 AS percentil
+		FROM (%s) r
+		JOIN orgaos o ON o.id = r.orgao
+		WHERE %s
+		ORDER BY posicao, r.orgao`, source, strings.Join(where, " AND "))
+
+	var dtoEntries []dto.RankingEntryDTO
+	if err := p.reader().Raw(query, args...).Scan(&dtoEntries).Error; err != nil {
+		return nil, fmt.Errorf("error getting ranking: %w", classify(err))
+	}
+	var entries []models.RankingEntry
+	for _, e := range dtoEntries {
+		entries = append(entries, *e.ConvertToModel())
+	}
+	return entries, nil
+}
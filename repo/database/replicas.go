@@ -0,0 +1,134 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultReplicaHealthCheckInterval = 10 * time.Second
+	replicaHealthCheckTimeout         = 5 * time.Second
+)
+
+// replica é uma réplica de leitura do banco de dados.
+type replica struct {
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+// replicaSet distribui as leituras entre as réplicas saudáveis (round-robin) e verifica
+// periodicamente a saúde de cada uma delas.
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func newReplicaSet(dbs []*gorm.DB) *replicaSet {
+	rs := &replicaSet{stop: make(chan struct{})}
+	for _, db := range dbs {
+		r := &replica{db: db}
+		rs.replicas = append(rs.replicas, r)
+	}
+	rs.checkHealth()
+	return rs
+}
+
+// pick retorna a próxima réplica saudável ou nil, caso nenhuma esteja disponível.
+func (rs *replicaSet) pick() *gorm.DB {
+	if rs == nil {
+		return nil
+	}
+	n := uint64(len(rs.replicas))
+	for i := uint64(0); i < n; i++ {
+		r := rs.replicas[(rs.next.Add(1)-1)%n]
+		if r.healthy.Load() {
+			return r.db
+		}
+	}
+	return nil
+}
+
+// checkHealth verifica a conexão com cada uma das réplicas.
+func (rs *replicaSet) checkHealth() {
+	for _, r := range rs.replicas {
+		ctx, canc := context.WithTimeout(context.Background(), replicaHealthCheckTimeout)
+		r.healthy.Store(ping(ctx, r.db) == nil)
+		canc()
+	}
+}
+
+// start inicia a verificação periódica da saúde das réplicas.
+func (rs *replicaSet) start(interval time.Duration) {
+	if interval == 0 {
+		interval = defaultReplicaHealthCheckInterval
+	}
+	rs.wg.Add(1)
+	go func() {
+		defer rs.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				rs.checkHealth()
+			case <-rs.stop:
+				return
+			}
+		}
+	}()
+}
+
+// close interrompe a verificação de saúde e fecha as conexões com as réplicas. Pode ser
+// chamado mais de uma vez (por exemplo, em chamadas repetidas de Disconnect).
+func (rs *replicaSet) close() error {
+	if rs == nil {
+		return nil
+	}
+	rs.stopOnce.Do(func() { close(rs.stop) })
+	rs.wg.Wait()
+	for _, r := range rs.replicas {
+		db, err := r.db.DB()
+		if err != nil {
+			return err
+		}
+		if err := db.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// reader retorna a conexão que deve ser usada pelas consultas de leitura: uma réplica
+// saudável ou, se não houver nenhuma (ou se houve uma escrita dentro da janela de
+// read-your-writes), o banco primário.
+func (p *PostgresDB) reader() *gorm.DB {
+	if p.opts.ReadYourWrites > 0 {
+		if last := p.lastWrite.Load(); last > 0 && time.Since(time.Unix(0, last)) < p.opts.ReadYourWrites {
+			return p.db
+		}
+	}
+	if db := p.replicas.pick(); db != nil {
+		return db
+	}
+	return p.db
+}
+
+// markWrite registra o momento da última escrita no banco primário.
+func (p *PostgresDB) markWrite() {
+	p.lastWrite.Store(time.Now().UnixNano())
+}
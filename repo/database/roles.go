@@ -0,0 +1,108 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dadosjusbr/storage/models"
+	"github.com/dadosjusbr/storage/repo/database/dto"
+	"gorm.io/gorm/clause"
+)
+
+// normalizedRoleJoin associa a cada contracheque (tabela c) a função normalizada (fn.funcao)
+// do padrão mais longo de 'funcoes_normalizadas' que casa com sua função.
+const normalizedRoleJoin = `LEFT JOIN LATERAL (
+		SELECT f.funcao FROM funcoes_normalizadas f
+		WHERE c.funcao ILIKE f.padrao
+		ORDER BY LENGTH(f.padrao) DESC, f.padrao
+		LIMIT 1
+	) fn ON TRUE`
+
+// normalizedRole é a função normalizada do contracheque. Funções sem padrão cadastrado são
+// agrupadas pelo texto original, sem espaços nas bordas e em maiúsculas.
+const normalizedRole = "COALESCE(fn.funcao, UPPER(TRIM(c.funcao)), '')"
+
+// StoreRoleNormalizations armazena (ou atualiza) os padrões de normalização de funções.
+func (p *PostgresDB) StoreRoleNormalizations(rules []models.RoleNormalization) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	defer p.markWrite()
+	dtoRules := make([]dto.RoleNormalizationDTO, 0, len(rules))
+	for _, r := range rules {
+		dtoRules = append(dtoRules, *dto.NewRoleNormalizationDTO(r))
+	}
+	if err := p.db.Model(dto.RoleNormalizationDTO{}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "padrao"}},
+		UpdateAll: true,
+	}).Create(&dtoRules).Error; err != nil {
+		return fmt.Errorf("error inserting 'funcoes_normalizadas': %w", classify(err))
+	}
+	return nil
+}
+
+// GetRoleNormalizations retorna os padrões de normalização de funções, ordenados por função e padrão.
+func (p *PostgresDB) GetRoleNormalizations() ([]models.RoleNormalization, error) {
+	var dtoRules []dto.RoleNormalizationDTO
+	if err := p.reader().Model(&dto.RoleNormalizationDTO{}).Order("funcao, padrao").Find(&dtoRules).Error; err != nil {
+		return nil, fmt.Errorf("error getting role normalizations: %w", classify(err))
+	}
+	var rules []models.RoleNormalization
+	for _, r := range dtoRules {
+		rules = append(rules, *r.ConvertToModel())
+	}
+	return rules, nil
+}
+
+// GetRemunerationsByRole retorna os totais dos contracheques do órgão no período agrupados
+// pela função normalizada, ordenados pela remuneração total (desc).
+func (p *PostgresDB) GetRemunerationsByRole(agencyID string, period models.Period, opts ...models.AggregationOpts) ([]models.RemunerationGroup, error) {
+	return p.getRemunerationGroups(normalizedRole, normalizedRoleJoin, agencyID, period, opts)
+}
+
+// GetRemunerationsByWorkplace retorna os totais dos contracheques do órgão no período agrupados
+// pela lotação (sem espaços nas bordas e em maiúsculas), ordenados pela remuneração total (desc).
+func (p *PostgresDB) GetRemunerationsByWorkplace(agencyID string, period models.Period, opts ...models.AggregationOpts) ([]models.RemunerationGroup, error) {
+	return p.getRemunerationGroups("COALESCE(UPPER(TRIM(c.local_trabalho)), '')", "", agencyID, period, opts)
+}
+
+// getRemunerationGroups agrupa os contracheques do órgão no período pela expressão group. O
+// join, se houver, é feito apenas quando a expressão depende dele (como em normalizedRole).
+func (p *PostgresDB) getRemunerationGroups(group, join, agencyID string, period models.Period, opts []models.AggregationOpts) ([]models.RemunerationGroup, error) {
+	if err := period.Validate(); err != nil {
+		return nil, err
+	}
+	agencyID = strings.ToLower(agencyID)
+	d, err := p.newDeflation(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.check(inPeriod(p.reader().Table("contracheques c").Where("c.orgao = ?", agencyID), "c", period), "c"); err != nil {
+		return nil, err
+	}
+	f := d.factor("c")
+
+	query := fmt.Sprintf(`
+		%[1]s AS grupo,
+		COUNT(DISTINCT COALESCE(c.nome_sanitizado, c.nome)) AS num_membros,
+		COUNT(*) AS num_contracheques,
+		SUM(c.salario * %[2]s) AS remuneracao_base,
+		SUM(c.beneficios * %[2]s) AS outras_remuneracoes,
+		SUM(c.descontos * %[2]s) AS descontos,
+		SUM(c.remuneracao * %[2]s) AS remuneracoes`, group, f)
+	var dtoGroups []dto.RemunerationGroupDTO
+	m := p.reader().Table("contracheques c")
+	if join != "" {
+		m = m.Joins(join)
+	}
+	m = m.Where("c.orgao = ?", agencyID)
+	m = inPeriod(m, "c", period).Select(query).Group("grupo").Order("remuneracoes DESC, grupo")
+	if err := m.Scan(&dtoGroups).Error; err != nil {
+		return nil, fmt.Errorf("error getting remuneration groups: %w", classify(err))
+	}
+	var groups []models.RemunerationGroup
+	for _, g := range dtoGroups {
+		groups = append(groups, *g.ConvertToModel())
+	}
+	return groups, nil
+}
@@ -1,6 +1,7 @@
 package file_storage
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -24,7 +25,7 @@ func NewS3Client(region string, bucket string) (*S3Client, error) {
 		Region: aws.String(region),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error creating aws session: %w", err)
+		return nil, fmt.Errorf("error creating aws session: %w", classify(err))
 	}
 	s3Client := s3.New(sess)
 	return &S3Client{s3: s3Client, bucket: bucket}, nil
@@ -38,7 +39,7 @@ func (s S3Client) UploadFile(srcPath string, dstFolder string) (*models.Backup,
 
 	f, err := os.Open(srcPath)
 	if err != nil {
-		return nil, fmt.Errorf("Error opening file at %s: %v", srcPath, err)
+		return nil, fmt.Errorf("Error opening file at %s: %w", srcPath, classify(err))
 	}
 	defer f.Close()
 
@@ -48,12 +49,12 @@ func (s S3Client) UploadFile(srcPath string, dstFolder string) (*models.Backup,
 		Key:    aws.String(dstFolder),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("Error trying to upload file in S3 with key (%s): %v", dstFolder, err)
+		return nil, fmt.Errorf("Error trying to upload file in S3 with key (%s): %w", dstFolder, classify(err))
 	}
 
 	backup, err := s.GetFile(dstFolder)
 	if err != nil {
-		return nil, fmt.Errorf("Error getting backup file(%s): %q", dstFolder, err)
+		return nil, fmt.Errorf("Error getting backup file(%s): %w", dstFolder, err)
 	}
 	return backup, nil
 }
@@ -68,7 +69,7 @@ func (s S3Client) GetFile(dstFolder string) (*models.Backup, error) {
 	}
 	headObjectOutput, err := s.s3.HeadObjectWithContext(ctx, headObjectInput)
 	if err != nil {
-		return nil, fmt.Errorf("Error getting file metadata from (%s): %q", dstFolder, err)
+		return nil, fmt.Errorf("Error getting file metadata from (%s): %w", dstFolder, classify(err))
 	}
 	backup := &models.Backup{
 		Size: *headObjectOutput.ContentLength,
@@ -77,3 +78,30 @@ func (s S3Client) GetFile(dstFolder string) (*models.Backup, error) {
 	}
 	return backup, nil
 }
+
+func (s S3Client) DeleteFile(dstFolder string) error {
+	txn := s.newrelic.StartTransaction("aws.DeleteFile")
+	defer txn.End()
+	ctx := newrelic.NewContext(aws.BackgroundContext(), txn)
+	deleteObjectInput := &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(dstFolder),
+	}
+	if _, err := s.s3.DeleteObjectWithContext(ctx, deleteObjectInput); err != nil {
+		return fmt.Errorf("Error deleting file (%s) from S3: %w", dstFolder, classify(err))
+	}
+	return nil
+}
+
+func (s S3Client) Ping(ctx context.Context) error {
+	txn := s.newrelic.StartTransaction("aws.Ping")
+	defer txn.End()
+	ctx = newrelic.NewContext(ctx, txn)
+	headBucketInput := &s3.HeadBucketInput{
+		Bucket: aws.String(s.bucket),
+	}
+	if _, err := s.s3.HeadBucketWithContext(ctx, headBucketInput); err != nil {
+		return fmt.Errorf("Error reaching bucket (%s): %w", s.bucket, classify(err))
+	}
+	return nil
+}
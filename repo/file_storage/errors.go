@@ -0,0 +1,42 @@
+package file_storage
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/dadosjusbr/storage/models"
+)
+
+// classify wraps an error returned by S3 (or by the local file system) in a models.Error,
+// according to its kind. Errors that do not match any kind are returned unchanged.
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return models.NewError(models.ErrInvalidInput, err)
+	}
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		switch {
+		case reqErr.StatusCode() == http.StatusNotFound:
+			return models.NewError(models.ErrNotFound, err)
+		case reqErr.StatusCode() == http.StatusTooManyRequests, reqErr.StatusCode() >= http.StatusInternalServerError:
+			return models.NewError(models.ErrUnavailable, err)
+		}
+	}
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		switch awsErr.Code() {
+		case s3.ErrCodeNoSuchKey, s3.ErrCodeNoSuchBucket, "NotFound":
+			return models.NewError(models.ErrNotFound, err)
+		case request.ErrCodeRequestError, request.ErrCodeResponseTimeout, request.CanceledErrorCode:
+			return models.NewError(models.ErrUnavailable, err)
+		}
+	}
+	return err
+}
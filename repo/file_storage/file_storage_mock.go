@@ -5,6 +5,7 @@
 package file_storage
 
 import (
+	context "context"
 	reflect "reflect"
 
 	models "github.com/dadosjusbr/storage/models"
@@ -34,6 +35,20 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// DeleteFile mocks base method.
+func (m *MockInterface) DeleteFile(dstFolder string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFile", dstFolder)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockInterfaceMockRecorder) DeleteFile(dstFolder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockInterface)(nil).DeleteFile), dstFolder)
+}
+
 // GetFile mocks base method.
 func (m *MockInterface) GetFile(dstFolder string) (*models.Backup, error) {
 	m.ctrl.T.Helper()
@@ -49,6 +64,20 @@ func (mr *MockInterfaceMockRecorder) GetFile(dstFolder interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockInterface)(nil).GetFile), dstFolder)
 }
 
+// Ping mocks base method.
+func (m *MockInterface) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockInterfaceMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockInterface)(nil).Ping), ctx)
+}
+
 // UploadFile mocks base method.
 func (m *MockInterface) UploadFile(srcPath, dstFolder string) (*models.Backup, error) {
 	m.ctrl.T.Helper()
@@ -1,10 +1,15 @@
 package file_storage
 
 import (
+	"context"
+
 	"github.com/dadosjusbr/storage/models"
 )
 
 type Interface interface {
 	UploadFile(srcPath string, dstFolder string) (*models.Backup, error)
 	GetFile(dstFolder string) (*models.Backup, error)
+	DeleteFile(dstFolder string) error
+	// Ping checks whether the bucket is reachable.
+	Ping(ctx context.Context) error
 }
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dadosjusbr/storage/models"
+)
+
+// summaryTolerance is the maximum difference between a stored and a computed value
+// for them to be considered equal (values are in BRL).
+const summaryTolerance = 0.01
+
+// compareSummaries returns the fields whose values differ between the stored and the computed summaries.
+func compareSummaries(stored, computed models.Summary) []models.SummaryDiscrepancy {
+	var discrepancies []models.SummaryDiscrepancy
+	compare := func(field string, s, c float64) {
+		if math.Abs(s-c) > summaryTolerance {
+			discrepancies = append(discrepancies, models.SummaryDiscrepancy{Field: field, Stored: s, Computed: c})
+		}
+	}
+	compare("membros", float64(stored.Count), float64(computed.Count))
+	compareData := func(field string, s, c models.DataSummary) {
+		compare(field+".maximo", s.Max, c.Max)
+		compare(field+".minimo", s.Min, c.Min)
+		compare(field+".media", s.Average, c.Average)
+		compare(field+".total", s.Total, c.Total)
+	}
+	compareData("remuneracao_base", stored.BaseRemuneration, computed.BaseRemuneration)
+	compareData("outras_remuneracoes", stored.OtherRemunerations, computed.OtherRemunerations)
+	compareData("descontos", stored.Discounts, computed.Discounts)
+	compareData("remuneracoes", stored.Remunerations, computed.Remunerations)
+
+	buckets := make(map[int]bool)
+	for b := range stored.IncomeHistogram {
+		buckets[b] = true
+	}
+	for b := range computed.IncomeHistogram {
+		buckets[b] = true
+	}
+	for _, b := range sortedKeys(buckets) {
+		compare(fmt.Sprintf("histograma_renda.%d", b), float64(stored.IncomeHistogram[b]), float64(computed.IncomeHistogram[b]))
+	}
+
+	items := make(map[string]bool)
+	for i := range stored.ItemSummary {
+		items[i] = true
+	}
+	for i := range computed.ItemSummary {
+		items[i] = true
+	}
+	for _, i := range sortedKeys(items) {
+		compare("resumo_rubricas."+i, stored.ItemSummary[i], computed.ItemSummary[i])
+	}
+	return discrepancies
+}
+
+func sortedKeys[K int | string](m map[K]bool) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
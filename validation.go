@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dadosjusbr/storage/models"
+)
+
+// minYear is the earliest year accepted in writes.
+const minYear = 2000
+
+// Names (json) of the agency, month and year fields of each kind of record.
+var (
+	monthlyInfoFields = [3]string{"aid", "month", "year"}
+	paycheckFields    = [3]string{"orgao", "mes", "ano"}
+)
+
+// agencyMonth identifies the agency-month of a write. All records of a write must
+// belong to the same agency-month.
+type agencyMonth struct {
+	agency string
+	month  int
+	year   int
+}
+
+// validator accumulates the field errors found while validating the input of a write.
+// Every write method of the Client validates its input before reaching the database.
+type validator struct {
+	models.ValidationError
+	// Agency-month of the first validated record, used to check the consistency of the batch.
+	batch *agencyMonth
+	// Agency IDs referenced by the write, with the field of the first reference.
+	agencies map[string]string
+}
+
+func newValidator() *validator {
+	return &validator{agencies: make(map[string]string)}
+}
+
+// agencyMonth validates the agency ID, month and year of a record and checks whether
+// they are the same as the ones of the other records of the write.
+func (v *validator) agencyMonth(field string, names [3]string, agency string, month, year int) {
+	if agency == "" {
+		v.Add(field+"."+names[0], "cannot be empty")
+	} else if _, ok := v.agencies[agency]; !ok {
+		v.agencies[agency] = field + "." + names[0]
+	}
+	if month < 1 || month > 12 {
+		v.Add(field+"."+names[1], "must be between 1 and 12, got %d", month)
+	}
+	if year < minYear || year > time.Now().Year() {
+		v.Add(field+"."+names[2], "must be between %d and %d, got %d", minYear, time.Now().Year(), year)
+	}
+	am := agencyMonth{agency: agency, month: month, year: year}
+	if v.batch == nil {
+		v.batch = &am
+	} else if *v.batch != am {
+		v.Add(field, "belongs to %s/%02d/%d, but the write is for %s/%02d/%d", agency, month, year, v.batch.agency, v.batch.month, v.batch.year)
+	}
+}
+
+func (v *validator) nonNegative(field string, value float64) {
+	if value < 0 {
+		v.Add(field, "cannot be negative, got %v", value)
+	}
+}
+
+func (v *validator) monthlyInfo(field string, agmi models.AgencyMonthlyInfo) {
+	v.agencyMonth(field, monthlyInfoFields, agmi.AgencyID, agmi.Month, agmi.Year)
+	v.nonNegative(field+".duration", agmi.Duration)
+	if agmi.Summary != nil {
+		v.nonNegative(field+".summary.membros", float64(agmi.Summary.Count))
+		for bucket, count := range agmi.Summary.IncomeHistogram {
+			v.nonNegative(fmt.Sprintf("%s.summary.histograma_renda[%d]", field, bucket), float64(count))
+		}
+	}
+}
+
+func (v *validator) paychecks(p []models.Paycheck, r []models.PaycheckItem) {
+	ids := make(map[int]bool, len(p))
+	for i, pc := range p {
+		field := fmt.Sprintf("paychecks[%d]", i)
+		v.agencyMonth(field, paycheckFields, pc.Agency, pc.Month, pc.Year)
+		if pc.ID <= 0 {
+			v.Add(field+".id", "must be positive, got %d", pc.ID)
+		}
+		if ids[pc.ID] {
+			v.Add(field+".id", "duplicated paycheck id %d", pc.ID)
+		}
+		ids[pc.ID] = true
+	}
+	type itemKey struct{ id, paycheckID int }
+	items := make(map[itemKey]bool, len(r))
+	for i, pi := range r {
+		field := fmt.Sprintf("paycheck_items[%d]", i)
+		v.agencyMonth(field, paycheckFields, pi.Agency, pi.Month, pi.Year)
+		if !ids[pi.PaycheckID] {
+			v.Add(field+".id_contracheque", "references paycheck %d, which is not in the write", pi.PaycheckID)
+		}
+		key := itemKey{pi.ID, pi.PaycheckID}
+		if items[key] {
+			v.Add(field+".id", "duplicated item id %d for paycheck %d", pi.ID, pi.PaycheckID)
+		}
+		items[key] = true
+	}
+}
+
+func (v *validator) retroactivePayments(rp []models.RetroactivePayments) {
+	for i, r := range rp {
+		v.agencyMonth(fmt.Sprintf("retroactive_payments[%d]", i), paycheckFields, r.Agency, r.Month, r.Year)
+	}
+}
+
+func (v *validator) remunerations(field string, remu models.Remunerations) {
+	v.agencyMonth(field, monthlyInfoFields, remu.AgencyID, remu.Month, remu.Year)
+	v.nonNegative(field+".num_base", float64(remu.NumBase))
+	v.nonNegative(field+".num_descontos", float64(remu.NumDiscounts))
+	v.nonNegative(field+".num_outras", float64(remu.NumOther))
+	if remu.ZipUrl == "" {
+		v.Add(field+".zip_url", "cannot be empty")
+	}
+}
+
+func (v *validator) remunerationCaps(caps []models.RemunerationCap) {
+	months := make(map[[2]int]bool, len(caps))
+	for i, c := range caps {
+		field := fmt.Sprintf("caps[%d]", i)
+		if c.Month < 1 || c.Month > 12 {
+			v.Add(field+".month", "must be between 1 and 12, got %d", c.Month)
+		}
+		// Caps may be registered before they come into effect, so there is no upper bound for the year.
+		if c.Year < minYear {
+			v.Add(field+".year", "must be at least %d, got %d", minYear, c.Year)
+		}
+		if c.Value <= 0 {
+			v.Add(field+".value", "must be positive, got %v", c.Value)
+		}
+		if months[[2]int{c.Year, c.Month}] {
+			v.Add(field, "duplicated cap for %02d/%d", c.Month, c.Year)
+		}
+		months[[2]int{c.Year, c.Month}] = true
+	}
+}
+
+func (v *validator) priceIndex(series []models.PriceIndex) {
+	months := make(map[[2]int]bool, len(series))
+	for i, pi := range series {
+		field := fmt.Sprintf("price_index[%d]", i)
+		if pi.Month < 1 || pi.Month > 12 {
+			v.Add(field+".month", "must be between 1 and 12, got %d", pi.Month)
+		}
+		if pi.Value <= 0 {
+			v.Add(field+".value", "must be positive, got %v", pi.Value)
+		}
+		if months[[2]int{pi.Year, pi.Month}] {
+			v.Add(field, "duplicated value for %02d/%d", pi.Month, pi.Year)
+		}
+		months[[2]int{pi.Year, pi.Month}] = true
+	}
+}
+
+func (v *validator) roleNormalizations(rules []models.RoleNormalization) {
+	patterns := make(map[string]bool, len(rules))
+	for i, r := range rules {
+		field := fmt.Sprintf("roles[%d]", i)
+		if strings.TrimSpace(r.Pattern) == "" {
+			v.Add(field+".pattern", "cannot be empty")
+		}
+		if strings.TrimSpace(r.Role) == "" {
+			v.Add(field+".role", "cannot be empty")
+		}
+		if patterns[strings.ToLower(r.Pattern)] {
+			v.Add(field, "duplicated pattern %q", r.Pattern)
+		}
+		patterns[strings.ToLower(r.Pattern)] = true
+	}
+}
+
+func (v *validator) anomalies(agency string, period models.Period, anomalies []models.Anomaly) {
+	if err := period.Validate(); err != nil {
+		v.Add("period", "%s", err)
+	}
+	if agency == "" {
+		v.Add("aid", "cannot be empty")
+	} else {
+		v.agencies[agency] = "aid"
+	}
+	from, to := period.FromYear*12+period.FromMonth, period.ToYear*12+period.ToMonth
+	for i, a := range anomalies {
+		field := fmt.Sprintf("anomalies[%d]", i)
+		if a.AgencyID != agency {
+			v.Add(field+".aid", "must be %s, got %s", agency, a.AgencyID)
+		}
+		if m := a.Year*12 + a.Month; a.Month < 1 || a.Month > 12 || m < from || m > to {
+			v.Add(field, "%02d/%d is not in the period %s", a.Month, a.Year, period)
+		}
+	}
+}
+
+// validate checks that the agencies referenced by the write exist and returns the
+// aggregated validation error, if any.
+func (c *Client) validate(v *validator) error {
+	agencies := make([]string, 0, len(v.agencies))
+	for agency := range v.agencies {
+		agencies = append(agencies, agency)
+	}
+	sort.Strings(agencies)
+	for _, agency := range agencies {
+		if _, err := c.Db.GetAgency(agency); err != nil {
+			if !errors.Is(err, models.ErrNotFound) {
+				return fmt.Errorf("error checking agency %s: %w", agency, err)
+			}
+			v.Add(v.agencies[agency], "agency %s does not exist", agency)
+		}
+	}
+	return v.Err()
+}